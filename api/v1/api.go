@@ -0,0 +1,22 @@
+// Package v1 assembles the routers of the public v1 HTTP API.
+//
+// @title    example API
+// @version  1.0
+// @BasePath /api
+//
+// @securityDefinitions.apikey BearerAuth
+// @in                         header
+// @name                       Authorization
+package v1
+
+import (
+	_ "example/docs"
+	"example/internal/handler/user"
+	"example/pkg/server"
+)
+
+func Routers(users user.Repository) []server.RouterHTTP {
+	return []server.RouterHTTP{
+		{Pattern: "/v1/users", Handler: user.New(users).Router()},
+	}
+}
@@ -0,0 +1,158 @@
+package main
+
+import (
+	"context"
+	"os"
+	"time"
+
+	v1 "example/api/v1"
+	"example/internal/config"
+	"example/internal/repository"
+	"example/migration"
+	"example/pkg/auth"
+	"example/pkg/closer"
+	pkgconfig "example/pkg/config"
+	"example/pkg/healthcheck"
+	"example/pkg/logger"
+	"example/pkg/server"
+	mwhttp "example/pkg/server/middleware/http"
+	"example/pkg/storage/mysql"
+	"example/pkg/tracing"
+
+	"go.uber.org/zap"
+)
+
+func main() {
+	ctx := context.Background()
+
+	cfgWatcher, err := config.WatchConfig()
+	if err != nil {
+		fail(ctx, "can't load config", err)
+	}
+	closer.Add(cfgWatcher.Close)
+
+	cfg := cfgWatcher.Get()
+	logger.SetLogger(logger.NewWithOptions(nil, loggerOptions(cfg)...))
+	logger.SetLevel(cfg.App.LogLevel)
+
+	shutdownTracing, err := tracing.Init(tracingConfig(cfg))
+	if err != nil {
+		fail(ctx, "can't init tracing", err)
+	}
+	closer.Add(shutdownTracing)
+
+	storage, err := mysql.New(mysqlConfig(cfg))
+	if err != nil {
+		fail(ctx, "can't connect to MySQL", err)
+	}
+	closer.Add(storage.Close)
+	healthcheck.Register("mysql", storage)
+
+	cfgWatcher.Subscribe(func(old, new *config.Config) {
+		if new.App.LogLevel != old.App.LogLevel {
+			logger.SetLevel(new.App.LogLevel)
+		}
+		if new.DB.DB.MaxOpen != old.DB.DB.MaxOpen {
+			storage.Master().SetMaxOpenConns(new.DB.DB.MaxOpen)
+		}
+	})
+
+	users := repository.NewUserRepo(storage, logger.NewContextLogger("repository"))
+
+	// Read the secret per token, it changes with a config reload or a
+	// rotated file:// secret.
+	jwt, err := auth.NewHS256Func(func() []byte { return cfgWatcher.Get().JWTSecret.Bytes() })
+	if err != nil {
+		fail(ctx, "can't init JWT", err)
+	}
+
+	srvCfg := serverConfig(cfg)
+	srvCfg.HTTPOptions = append(srvCfg.HTTPOptions, mwhttp.WithCustomMiddleware(
+		auth.Middleware(jwt, auth.WithPublicPaths("/api/status", "/swagger/")),
+	))
+
+	srv, err := server.New(srvCfg)
+	if err != nil {
+		fail(ctx, "can't start server", err)
+	}
+
+	srv.Run(v1.Routers(users))
+}
+
+func loggerOptions(cfg *config.Config) []logger.Option {
+	opts := []logger.Option{
+		logger.WithEncoding(cfg.App.LogFormat),
+		logger.WithRedaction(logger.RedactPolicyFor(cfg.App.Env)),
+		logger.WithStaticFields(
+			zap.String("app", cfg.App.Name),
+			zap.String("env", cfg.App.Env),
+			zap.String("host", pkgconfig.HostName),
+		),
+	}
+	if cfg.App.LogFile != "" {
+		opts = append(opts, logger.WithOutput(os.Stdout), logger.WithFile(cfg.App.LogFile, 100, 5, 7))
+	}
+	if cfg.App.LogSampling {
+		opts = append(opts, logger.WithSampling(time.Second, 100, 100))
+	}
+	return opts
+}
+
+func tracingConfig(cfg *config.Config) tracing.Config {
+	return tracing.Config{
+		ServiceName: cfg.App.Name,
+		Env:         cfg.App.Env,
+		HostName:    pkgconfig.HostName,
+		Ratio:       cfg.App.TraceRatio,
+		Exporter:    cfg.Tracing.Exporter,
+		Endpoint:    cfg.Tracing.Endpoint,
+		Insecure:    cfg.Tracing.Insecure,
+		File:        cfg.Tracing.File,
+	}
+}
+
+func serverConfig(cfg *config.Config) *server.Config {
+	return &server.Config{
+		Env:            cfg.App.Env,
+		Name:           cfg.App.Name,
+		Swagger:        cfg.Server.WithSwagger,
+		Logging:        cfg.Server.Logging,
+		HTTPPort:       cfg.Server.HTTPPort,
+		GrpcPort:       cfg.Server.GrpcPort,
+		MonitoringPort: cfg.Server.MonitoringPort,
+	}
+}
+
+func mysqlConfig(cfg *config.Config) mysql.Config {
+	c := mysql.Config{
+		DBconfig:    nodeConfig(cfg.DB.DB),
+		Balancer:    mysql.Balancer(cfg.DB.Balancer),
+		RouteReads:  cfg.DB.RouteReads,
+		Metrics:     cfg.DB.Metrics,
+		Migrations:  cfg.DB.Migrations,
+		MigrationFS: migration.FS,
+	}
+	for i := range cfg.DB.Replicas {
+		c.Replicas = append(c.Replicas, nodeConfig(cfg.DB.Replicas[i]))
+	}
+	return c
+}
+
+func nodeConfig(cfg config.DBConfig) mysql.NodeConfig {
+	return mysql.NodeConfig{
+		Host:         cfg.Host,
+		Port:         cfg.Port,
+		User:         cfg.User,
+		Password:     cfg.Password.Value(),
+		PasswordFunc: cfg.Password.Value,
+		Database:     cfg.Database,
+		MaxOpen:      cfg.MaxOpen,
+		TimeOut:      cfg.Timeout,
+	}
+}
+
+// fail closes whatever was already started and exits with a non-zero code.
+func fail(ctx context.Context, msg string, err error) {
+	closer.CloseAll()
+	logger.Fatalf(ctx, "%s: %v", msg, err)
+}
@@ -0,0 +1,94 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"example/internal/config"
+)
+
+const usage = `usage: config [-config path] <command>
+
+commands:
+  dump          print the effective config values
+  explain       print the effective config values and where each one came from
+  schema        print the JSON schema of the config
+
+flags:
+`
+
+func main() {
+	configPath := flag.String("config", "config/values.json", "path to the config file")
+	asJSON := flag.Bool("json", false, "print dump and explain as JSON")
+	flag.Usage = func() {
+		fmt.Fprint(flag.CommandLine.Output(), usage)
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	if flag.NArg() != 1 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	var err error
+	switch cmd := flag.Arg(0); cmd {
+	case "dump", "explain":
+		err = explain(*configPath, cmd == "explain", *asJSON)
+	case "schema":
+		err = printJSON(config.Schema())
+	default:
+		err = fmt.Errorf("unknown command %q", cmd)
+	}
+	if err != nil {
+		fail(err)
+	}
+}
+
+func explain(configPath string, withSource, asJSON bool) error {
+	entries, err := config.Explain(config.Options(configPath))
+	if entries == nil {
+		return err
+	}
+
+	if asJSON {
+		if !withSource {
+			for i := range entries {
+				entries[i].Source = ""
+			}
+		}
+		if jsonErr := printJSON(entries); jsonErr != nil {
+			return jsonErr
+		}
+		return err
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	for _, e := range entries {
+		if withSource {
+			fmt.Fprintf(w, "%s\t%s\t%s\n", e.Key, e.Value, e.Source)
+		} else {
+			fmt.Fprintf(w, "%s\t%s\n", e.Key, e.Value)
+		}
+	}
+	if flushErr := w.Flush(); flushErr != nil {
+		return flushErr
+	}
+
+	// Invalid values are still printed so the bad source can be found.
+	return err
+}
+
+func printJSON(v interface{}) error {
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
+
+func fail(err error) {
+	fmt.Fprintf(os.Stderr, "config: %v\n", err)
+	os.Exit(1)
+}
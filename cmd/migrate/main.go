@@ -0,0 +1,102 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"example/internal/config"
+	"example/migration"
+	pkgconfig "example/pkg/config"
+	"example/pkg/storage/mysql"
+)
+
+const usage = `usage: migrate [-config path] <command>
+
+commands:
+  up            apply all pending migrations
+  down          roll back the last -steps migrations
+  status        list migrations and whether they are applied
+
+flags:
+`
+
+func main() {
+	configPath := flag.String("config", "config/values.json", "path to the config file")
+	steps := flag.Int("steps", 1, "number of migrations to roll back with down")
+	flag.Usage = func() {
+		fmt.Fprint(flag.CommandLine.Output(), usage)
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	if flag.NArg() != 1 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	// Resolve the config like the app does, with .env, Consul and Vault, but
+	// only the db part so unrelated settings need not be present.
+	var db config.DB
+	if err := pkgconfig.NewLoader(config.Options(*configPath)).LoadSub("db", &db); err != nil {
+		fail(err)
+	}
+
+	m, err := mysql.NewMigrator(mysql.NodeConfig{
+		Host:         db.DB.Host,
+		Port:         db.DB.Port,
+		User:         db.DB.User,
+		Password:     db.DB.Password.Value(),
+		PasswordFunc: db.DB.Password.Value,
+		Database:     db.DB.Database,
+		TimeOut:      db.DB.Timeout,
+	}, migration.FS)
+	if err != nil {
+		fail(err)
+	}
+	defer m.Close()
+
+	ctx := context.Background()
+	switch cmd := flag.Arg(0); cmd {
+	case "up":
+		err = m.Up(ctx)
+	case "down":
+		err = m.Down(ctx, *steps)
+	case "status":
+		err = printStatus(ctx, m)
+	default:
+		err = fmt.Errorf("unknown command %q", cmd)
+	}
+	if err != nil {
+		_ = m.Close()
+		fail(err)
+	}
+}
+
+func printStatus(ctx context.Context, m *mysql.Migrator) error {
+	status, err := m.Status(ctx)
+	if err != nil {
+		return err
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "VERSION\tNAME\tAPPLIED AT")
+	for _, st := range status {
+		appliedAt := "pending"
+		if st.Applied {
+			appliedAt = st.AppliedAt.Format("2006-01-02 15:04:05")
+		}
+		if st.Dirty {
+			appliedAt += " (dirty)"
+		}
+		fmt.Fprintf(w, "%d\t%s\t%s\n", st.Version, st.Name, appliedAt)
+	}
+	return w.Flush()
+}
+
+func fail(err error) {
+	fmt.Fprintf(os.Stderr, "migrate: %v\n", err)
+	os.Exit(1)
+}
@@ -6,10 +6,10 @@ import (
 )
 
 type Config struct {
-	App       App    `mapstructure:"app"`
-	Server    Server `mapstructure:"server"`
-	DB        DB     `mapstructure:"db"`
-	JWTSecret string `mapstructure:"jwt_secret"`
+	App       App           `mapstructure:"app"`
+	Server    Server        `mapstructure:"server"`
+	DB        DB            `mapstructure:"db"`
+	JWTSecret config.Secret `mapstructure:"jwt_secret"`
 }
 
 type App struct {
@@ -33,11 +33,11 @@ type DB struct {
 }
 
 type DBConfig struct {
-	Host     string              `mapstructure:"host"`
-	Port     string              `mapstructure:"port"`
-	User     string              `mapstructure:"user"`
-	Password config.SecretString `mapstructure:"password"`
-	Database string              `mapstructure:"database"`
-	MaxOpen  uint                `mapstructure:"max_open"`
-	Timeout  time.Duration       `mapstructure:"timeout"`
+	Host     string        `mapstructure:"host"`
+	Port     string        `mapstructure:"port"`
+	User     string        `mapstructure:"user"`
+	Password config.Secret `mapstructure:"password"`
+	Database string        `mapstructure:"database"`
+	MaxOpen  uint          `mapstructure:"max_open"`
+	Timeout  time.Duration `mapstructure:"timeout"`
 }
@@ -6,48 +6,138 @@ import (
 	"example/pkg/logger"
 	"os"
 	"path"
+	"time"
+)
+
+const (
+	consulAddrKey = "CONSUL_HTTP_ADDR"
+	consulKeyKey  = "CONSUL_CONFIG_KEY"
+	vaultAddrKey  = "VAULT_ADDR"
+	vaultPathKey  = "VAULT_SECRET_PATH"
+
+	remoteReloadInterval = time.Minute
 )
 
 func GetConfig() *Config {
-	confOpts := config.Options{
-		ReplaceFromEnvVars: true,
+	return NewConfig(getOptions())
+}
+
+// WatchConfig loads the config like GetConfig and keeps reloading it on change.
+func WatchConfig() (*config.Watcher[Config], error) {
+	opts := getOptions()
+	names, err := config.NewLoader(opts).EnvNames()
+	if err != nil {
+		return nil, err
 	}
+	return config.NewWatcher(opts, defaultConfig, fillDefaults(names))
+}
 
+func getOptions() config.Options {
 	var configPath string
 	if len(os.Args) > 1 {
 		configPath = os.Args[1]
 	} else {
 		configPath = os.Getenv("CONFIG_FILE")
 	}
+
+	return Options(configPath)
+}
+
+// Options returns the options the app loads its config with, reading the
+// file at configPath or the default one if it is empty.
+func Options(configPath string) config.Options {
+	confOpts := config.Options{
+		DotEnv:             []string{".env"},
+		ReplaceFromEnvVars: true,
+		ReloadInterval:     remoteReloadInterval,
+	}
+
 	if configPath != "" {
 		confOpts.Dir = path.Dir(configPath)
 		confOpts.File = path.Base(configPath)
 	}
 
-	return NewConfig(confOpts)
+	if os.Getenv(consulAddrKey) != "" {
+		confOpts.Consul = &config.ConsulOptions{Key: os.Getenv(consulKeyKey)}
+	}
+	if os.Getenv(vaultAddrKey) != "" {
+		confOpts.Vault = &config.VaultOptions{Path: os.Getenv(vaultPathKey)}
+	}
+
+	return confOpts
 }
 
 func NewConfig(opts config.Options) *Config {
-	var cfg = &Config{
-		App: App{
-			Env:      "undefined",
-			Name:     "undefined",
-			LogLevel: "debug",
-		},
-	}
+	cfg := defaultConfig()
 
-	err := config.NewConfig(cfg, opts)
+	l := config.NewLoader(opts)
+	if err := l.Load(cfg); err != nil {
+		logger.Fatal(context.Background(), err)
+	}
+	names, err := l.EnvNames()
 	if err != nil {
 		logger.Fatal(context.Background(), err)
 	}
+	_ = fillDefaults(names)(cfg)
 
-	if cfg.App.Name == "" {
-		cfg.App.Name = config.AppName
+	return cfg
+}
+
+// Explain lists the effective config values and their sources, see config.Loader.Explain.
+func Explain(opts config.Options) ([]config.Entry, error) {
+	cfg := defaultConfig()
+
+	l := config.NewLoader(opts)
+	entries, err := l.Explain(cfg)
+	if entries == nil {
+		return nil, err
 	}
+	names, nerr := l.EnvNames()
+	if nerr != nil {
+		return nil, nerr
+	}
+	_ = fillDefaults(names)(cfg)
 
-	if cfg.App.Env == "" {
-		cfg.App.Env = config.EnvName
+	for i, e := range entries {
+		var filled string
+		switch e.Key {
+		case "app.name":
+			filled = cfg.App.Name
+		case "app.env":
+			filled = cfg.App.Env
+		default:
+			continue
+		}
+		if e.Value != filled {
+			entries[i].Value = filled
+			entries[i].Source = "default:env"
+		}
 	}
 
-	return cfg
+	return entries, err
+}
+
+// Schema returns the JSON schema of Config.
+func Schema() map[string]interface{} {
+	return config.JSONSchema(Config{})
+}
+
+func defaultConfig() *Config {
+	return &Config{}
+}
+
+// fillDefaults names the app after the env params of the Loader, which may
+// come from its .env files.
+func fillDefaults(names config.EnvNames) func(cfg *Config) error {
+	return func(cfg *Config) error {
+		if cfg.App.Name == "" {
+			cfg.App.Name = names.AppName
+		}
+
+		if cfg.App.Env == "" {
+			cfg.App.Env = names.EnvName
+		}
+
+		return nil
+	}
 }
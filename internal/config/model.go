@@ -6,39 +6,53 @@ import (
 )
 
 type Config struct {
-	App       App    `mapstructure:"app"`
-	Server    Server `mapstructure:"server"`
-	DB        DB     `mapstructure:"db"`
-	JWTSecret string `mapstructure:"jwt_secret"`
+	App       App           `mapstructure:"app"`
+	Server    Server        `mapstructure:"server"`
+	DB        DB            `mapstructure:"db"`
+	Tracing   Tracing       `mapstructure:"tracing"`
+	JWTSecret config.Secret `mapstructure:"jwt_secret" validate:"required"`
 }
 
 type App struct {
-	Env        string  `mapstructure:"env"`
-	Name       string  `mapstructure:"name"`
-	LogLevel   string  `mapstructure:"log_level"`
-	TraceRatio float64 `mapstructure:"trace_ratio"`
+	Env         string  `mapstructure:"env"`
+	Name        string  `mapstructure:"name"`
+	LogLevel    string  `mapstructure:"log_level" default:"debug" validate:"oneof=debug info warn error dpanic panic fatal"`
+	LogFormat   string  `mapstructure:"log_format" default:"json" validate:"oneof=json console"`
+	LogFile     string  `mapstructure:"log_file"`
+	LogSampling bool    `mapstructure:"log_sampling"`
+	TraceRatio  float64 `mapstructure:"trace_ratio" validate:"min=0,max=1"`
+}
+
+type Tracing struct {
+	Exporter string `mapstructure:"exporter" default:"none" validate:"oneof=none otlp stdout file"`
+	Endpoint string `mapstructure:"endpoint"`
+	Insecure bool   `mapstructure:"insecure"`
+	File     string `mapstructure:"file" default:"traces.json"`
 }
 
 type Server struct {
-	HTTPPort       *uint `mapstructure:"http_port"`
-	GrpcPort       *uint `mapstructure:"grpc_port"`
-	MonitoringPort uint  `mapstructure:"monitoring_port"`
+	HTTPPort       *uint `mapstructure:"http_port" validate:"min=1,max=65535"`
+	GrpcPort       *uint `mapstructure:"grpc_port" validate:"min=1,max=65535"`
+	MonitoringPort uint  `mapstructure:"monitoring_port" validate:"max=65535"`
 	Logging        bool  `mapstructure:"logging"`
 	WithSwagger    bool  `mapstructure:"with_swagger"`
 }
 
 type DB struct {
-	DB         DBConfig `mapstructure:"db"`
-	Metrics    bool     `mapstructure:"metrics"`
-	Migrations bool     `mapstructure:"migrations"`
+	DB         DBConfig   `mapstructure:"db"`
+	Replicas   []DBConfig `mapstructure:"replicas"`
+	Balancer   string     `mapstructure:"balancer" validate:"oneof=round_robin least_conn"`
+	RouteReads bool       `mapstructure:"route_reads"`
+	Metrics    bool       `mapstructure:"metrics"`
+	Migrations bool       `mapstructure:"migrations"`
 }
 
 type DBConfig struct {
-	Host     string              `mapstructure:"host"`
-	Port     string              `mapstructure:"port"`
-	User     string              `mapstructure:"user"`
-	Password config.SecretString `mapstructure:"password"`
-	Database string              `mapstructure:"database"`
-	MaxOpen  uint                `mapstructure:"max_open"`
-	Timeout  time.Duration       `mapstructure:"timeout"`
+	Host     string        `mapstructure:"host" validate:"required"`
+	Port     string        `mapstructure:"port" validate:"required"`
+	User     string        `mapstructure:"user" validate:"required"`
+	Password config.Secret `mapstructure:"password"`
+	Database string        `mapstructure:"database" validate:"required"`
+	MaxOpen  uint          `mapstructure:"max_open"`
+	Timeout  time.Duration `mapstructure:"timeout" default:"5s" validate:"min=100ms"`
 }
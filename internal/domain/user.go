@@ -2,6 +2,6 @@ package domain
 
 type User struct {
 	UUID  string `db:"uuid"`
-	Name  string `db:"name"`
+	Name  string `db:"username"`
 	Email string `db:"email"`
 }
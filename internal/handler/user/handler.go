@@ -0,0 +1,243 @@
+package user
+
+import (
+	"context"
+	"database/sql"
+	"net/http"
+
+	"example/internal/domain"
+	"example/internal/uuid"
+	"example/pkg/server"
+	"example/pkg/storage/mysql"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+	"github.com/pkg/errors"
+)
+
+var (
+	errInvalidUUID = errors.New("invalid user uuid")
+	errInvalidBody = errors.New("invalid request body")
+	errValidation  = errors.New("validation failed")
+	errNotFound    = errors.New("user not found")
+	errEmailTaken  = errors.New("email is already taken")
+	errInternal    = errors.New("internal error")
+)
+
+type Repository interface {
+	GetAllUsers(ctx context.Context) ([]domain.User, error)
+	GetUser(ctx context.Context, UUID uuid.UUID) (*domain.User, error)
+	CreateUser(ctx context.Context, user domain.User) (uuid.UUID, error)
+	UpdateUser(ctx context.Context, user domain.User) (*domain.User, error)
+	DeleteUser(ctx context.Context, UUID uuid.UUID) error
+}
+
+type Handler struct {
+	repo Repository
+}
+
+func New(repo Repository) *Handler {
+	return &Handler{repo: repo}
+}
+
+func (h *Handler) Router() chi.Router {
+	r := chi.NewRouter()
+	r.Get("/", h.list)
+	r.Post("/", h.create)
+	r.Get("/{uuid}", h.get)
+	r.Patch("/{uuid}", h.update)
+	r.Delete("/{uuid}", h.delete)
+	return r
+}
+
+// list godoc
+//
+//	@Summary	List users
+//	@Tags		users
+//	@Security	BearerAuth
+//	@Produce	json
+//	@Success	200	{object}	listResponse
+//	@Failure	401	{object}	server.httpError
+//	@Failure	500	{object}	server.httpError
+//	@Router		/v1/users [get]
+func (h *Handler) list(w http.ResponseWriter, r *http.Request) {
+	users, err := h.repo.GetAllUsers(r.Context())
+	if err != nil {
+		server.ErrorJSON(w, r, http.StatusInternalServerError, errInternal)
+		return
+	}
+
+	resp := listResponse{Users: make([]userResponse, 0, len(users))}
+	for i := range users {
+		resp.Users = append(resp.Users, toResponse(users[i]))
+	}
+	server.ResponseJSON(w, r, resp)
+}
+
+// get godoc
+//
+//	@Summary	Get a user
+//	@Tags		users
+//	@Security	BearerAuth
+//	@Produce	json
+//	@Param		uuid	path		string	true	"User UUID"
+//	@Success	200		{object}	userResponse
+//	@Failure	400		{object}	server.httpError
+//	@Failure	401		{object}	server.httpError
+//	@Failure	404		{object}	server.httpError
+//	@Failure	500		{object}	server.httpError
+//	@Router		/v1/users/{uuid} [get]
+func (h *Handler) get(w http.ResponseWriter, r *http.Request) {
+	id, ok := userUUID(w, r)
+	if !ok {
+		return
+	}
+
+	user, err := h.repo.GetUser(r.Context(), id)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		server.ErrorJSON(w, r, http.StatusInternalServerError, errInternal)
+		return
+	}
+	if user == nil {
+		server.ErrorJSON(w, r, http.StatusNotFound, errNotFound)
+		return
+	}
+
+	server.ResponseJSON(w, r, toResponse(*user))
+}
+
+// create godoc
+//
+//	@Summary	Create a user
+//	@Tags		users
+//	@Security	BearerAuth
+//	@Accept		json
+//	@Produce	json
+//	@Param		user	body		createRequest	true	"User"
+//	@Success	201		{object}	userResponse
+//	@Failure	400		{object}	server.httpError
+//	@Failure	401		{object}	server.httpError
+//	@Failure	409		{object}	server.httpError
+//	@Failure	500		{object}	server.httpError
+//	@Router		/v1/users [post]
+func (h *Handler) create(w http.ResponseWriter, r *http.Request) {
+	var req createRequest
+	if err := render.DecodeJSON(r.Body, &req); err != nil {
+		server.ErrorJSON(w, r, http.StatusBadRequest, errInvalidBody)
+		return
+	}
+	if errs := req.validate(); len(errs) > 0 {
+		server.ErrorJSON(w, r, http.StatusBadRequest, errValidation, errs...)
+		return
+	}
+
+	user := domain.User{Name: req.Name, Email: req.Email}
+	id, err := h.repo.CreateUser(r.Context(), user)
+	if err != nil {
+		if mysql.IsDuplicate(err) {
+			server.ErrorJSON(w, r, http.StatusConflict, errEmailTaken, fieldError("email", errEmailTaken.Error()))
+			return
+		}
+		server.ErrorJSON(w, r, http.StatusInternalServerError, errInternal)
+		return
+	}
+	user.UUID = id.String()
+
+	server.ResponseJSONWithCode(w, r, http.StatusCreated, toResponse(user))
+}
+
+// update godoc
+//
+//	@Summary	Update a user
+//	@Tags		users
+//	@Security	BearerAuth
+//	@Accept		json
+//	@Produce	json
+//	@Param		uuid	path		string			true	"User UUID"
+//	@Param		user	body		updateRequest	true	"Fields to change"
+//	@Success	200		{object}	userResponse
+//	@Failure	400		{object}	server.httpError
+//	@Failure	401		{object}	server.httpError
+//	@Failure	404		{object}	server.httpError
+//	@Failure	409		{object}	server.httpError
+//	@Failure	500		{object}	server.httpError
+//	@Router		/v1/users/{uuid} [patch]
+func (h *Handler) update(w http.ResponseWriter, r *http.Request) {
+	id, ok := userUUID(w, r)
+	if !ok {
+		return
+	}
+
+	var req updateRequest
+	if err := render.DecodeJSON(r.Body, &req); err != nil {
+		server.ErrorJSON(w, r, http.StatusBadRequest, errInvalidBody)
+		return
+	}
+	if errs := req.validate(); len(errs) > 0 {
+		server.ErrorJSON(w, r, http.StatusBadRequest, errValidation, errs...)
+		return
+	}
+
+	user := domain.User{UUID: id.String()}
+	if req.Name != nil {
+		user.Name = *req.Name
+	}
+	if req.Email != nil {
+		user.Email = *req.Email
+	}
+
+	updated, err := h.repo.UpdateUser(r.Context(), user)
+	if err != nil {
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			server.ErrorJSON(w, r, http.StatusNotFound, errNotFound)
+		case mysql.IsDuplicate(err):
+			server.ErrorJSON(w, r, http.StatusConflict, errEmailTaken, fieldError("email", errEmailTaken.Error()))
+		default:
+			server.ErrorJSON(w, r, http.StatusInternalServerError, errInternal)
+		}
+		return
+	}
+
+	server.ResponseJSON(w, r, toResponse(*updated))
+}
+
+// delete godoc
+//
+//	@Summary	Delete a user
+//	@Tags		users
+//	@Security	BearerAuth
+//	@Param		uuid	path	string	true	"User UUID"
+//	@Success	204
+//	@Failure	400	{object}	server.httpError
+//	@Failure	401	{object}	server.httpError
+//	@Failure	404	{object}	server.httpError
+//	@Failure	500	{object}	server.httpError
+//	@Router		/v1/users/{uuid} [delete]
+func (h *Handler) delete(w http.ResponseWriter, r *http.Request) {
+	id, ok := userUUID(w, r)
+	if !ok {
+		return
+	}
+
+	err := h.repo.DeleteUser(r.Context(), id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			server.ErrorJSON(w, r, http.StatusNotFound, errNotFound)
+			return
+		}
+		server.ErrorJSON(w, r, http.StatusInternalServerError, errInternal)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func userUUID(w http.ResponseWriter, r *http.Request) (uuid.UUID, bool) {
+	id, err := uuid.Parse(chi.URLParam(r, "uuid"))
+	if err != nil {
+		server.ErrorJSON(w, r, http.StatusBadRequest, errInvalidUUID, fieldError("uuid", "must be a valid UUID"))
+		return "", false
+	}
+	return id, true
+}
@@ -0,0 +1,101 @@
+package user
+
+import (
+	"net/http"
+	"net/mail"
+	"strings"
+
+	"example/internal/domain"
+	"example/pkg/server"
+)
+
+const maxFieldLen = 255
+
+type userResponse struct {
+	UUID  string `json:"uuid"`
+	Name  string `json:"name"`
+	Email string `json:"email"`
+}
+
+type listResponse struct {
+	Users []userResponse `json:"users"`
+}
+
+type createRequest struct {
+	Name  string `json:"name"`
+	Email string `json:"email"`
+}
+
+type updateRequest struct {
+	Name  *string `json:"name"`
+	Email *string `json:"email"`
+}
+
+func toResponse(u domain.User) userResponse {
+	return userResponse{
+		UUID:  u.UUID,
+		Name:  u.Name,
+		Email: u.Email,
+	}
+}
+
+func (req *createRequest) validate() []server.ErrorDetail {
+	req.Name = strings.TrimSpace(req.Name)
+	req.Email = strings.TrimSpace(req.Email)
+
+	var errs []server.ErrorDetail
+	errs = append(errs, validateName(req.Name)...)
+	errs = append(errs, validateEmail(req.Email)...)
+	return errs
+}
+
+func (req *updateRequest) validate() []server.ErrorDetail {
+	if req.Name == nil && req.Email == nil {
+		return []server.ErrorDetail{{
+			Code:    http.StatusBadRequest,
+			Message: "at least one of name, email must be set",
+		}}
+	}
+
+	var errs []server.ErrorDetail
+	if req.Name != nil {
+		*req.Name = strings.TrimSpace(*req.Name)
+		errs = append(errs, validateName(*req.Name)...)
+	}
+	if req.Email != nil {
+		*req.Email = strings.TrimSpace(*req.Email)
+		errs = append(errs, validateEmail(*req.Email)...)
+	}
+	return errs
+}
+
+func validateName(name string) []server.ErrorDetail {
+	switch {
+	case name == "":
+		return []server.ErrorDetail{fieldError("name", "is required")}
+	case len(name) > maxFieldLen:
+		return []server.ErrorDetail{fieldError("name", "must be at most 255 characters")}
+	}
+	return nil
+}
+
+func validateEmail(email string) []server.ErrorDetail {
+	switch {
+	case email == "":
+		return []server.ErrorDetail{fieldError("email", "is required")}
+	case len(email) > maxFieldLen:
+		return []server.ErrorDetail{fieldError("email", "must be at most 255 characters")}
+	}
+	if addr, err := mail.ParseAddress(email); err != nil || addr.Address != email {
+		return []server.ErrorDetail{fieldError("email", "must be a valid email address")}
+	}
+	return nil
+}
+
+func fieldError(field, msg string) server.ErrorDetail {
+	return server.ErrorDetail{
+		Code:    http.StatusBadRequest,
+		Field:   field,
+		Message: msg,
+	}
+}
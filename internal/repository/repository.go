@@ -3,78 +3,105 @@ package repository
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"example/internal/domain"
 	UserUUIS "example/internal/uuid"
+	"example/pkg/logger"
 	"example/pkg/storage/mysql"
-	"go.uber.org/zap"
 	"strings"
 )
 
+// Events are logged at Debug for reads and attempts, Info for changes, Warn
+// for outcomes caused by the caller, e.g. not found or a duplicate email, and
+// Error for database failures.
+const (
+	eventUserList         = "user.list"
+	eventUserListFailed   = "user.list.failed"
+	eventUserGet          = "user.get"
+	eventUserNotFound     = "user.not_found"
+	eventUserGetFailed    = "user.get.failed"
+	eventUserCreate       = "user.create"
+	eventUserCreated      = "user.created"
+	eventUserDuplicate    = "user.duplicate"
+	eventUserCreateFailed = "user.create.failed"
+	eventUserUpdate       = "user.update"
+	eventUserUpdated      = "user.updated"
+	eventUserUpdateFailed = "user.update.failed"
+	eventUserDelete       = "user.delete"
+	eventUserDeleted      = "user.deleted"
+	eventUserDeleteFailed = "user.delete.failed"
+)
+
 type UserRepo struct {
 	db     mysql.MySQL
-	logger *zap.Logger
+	logger logger.ContextLogger
 }
 
-func NewUserRepo(db mysql.MySQL, logger *zap.Logger) *UserRepo {
-	return &UserRepo{db: db, logger: logger}
+// NewUserRepo returns a UserRepo logging to l, or to the global logger named
+// "repository" if l is nil.
+func NewUserRepo(db mysql.MySQL, l logger.ContextLogger) *UserRepo {
+	if l == nil {
+		l = logger.NewContextLogger("repository")
+	}
+	return &UserRepo{db: db, logger: l}
 }
 
+// GetAllUsers returns all users, an empty slice if there are none.
 func (ur *UserRepo) GetAllUsers(ctx context.Context) ([]domain.User, error) {
-	ur.logger.Info("UserRepo.GetAllUsers")
 	const query = `SELECT uuid, username, email FROM user`
 
-	var users []domain.User
+	users := []domain.User{}
 	err := ur.db.SelectContext(ctx, &users, query)
 	if err != nil {
-		ur.logger.Error("UserRepo.GetAllUsers select error", zap.Error(err))
+		ur.logger.ErrorKV(ctx, eventUserListFailed, "error", err)
 		return nil, err
 	}
 
-	if len(users) == 0 {
-		ur.logger.Error("UserRepo.GetAllUsers no users found", zap.Error(sql.ErrNoRows))
-		return nil, sql.ErrNoRows
-	}
+	ur.logger.DebugKV(ctx, eventUserList, "count", len(users))
 	return users, nil
 }
 
+// GetUser returns nil without an error if there is no user with UUID.
 func (ur *UserRepo) GetUser(ctx context.Context, UUID UserUUIS.UUID) (*domain.User, error) {
-	ur.logger.Info("UserRepo.GetUser", zap.String("uuid", UUID.String()))
+	ur.logger.DebugKV(ctx, eventUserGet, "uuid", UUID.String())
 	const query = `SELECT uuid, username, email FROM user WHERE uuid = ?`
 
 	var user domain.User
 	err := ur.db.GetContext(ctx, &user, query, UUID.String())
 	if err != nil {
-		if err == sql.ErrNoRows {
-			ur.logger.Warn("UserRepo.GetUser: no user found", zap.String("UUID", UUID.String()))
+		if errors.Is(err, sql.ErrNoRows) {
+			ur.logger.WarnKV(ctx, eventUserNotFound, "uuid", UUID.String())
 			return nil, nil
 		}
-		ur.logger.Error("UserRepo.GetUser query error", zap.Error(err))
+		ur.logger.ErrorKV(ctx, eventUserGetFailed, "uuid", UUID.String(), "error", err)
 		return nil, err
 	}
 	return &user, nil
 }
 
 func (ur *UserRepo) CreateUser(ctx context.Context, user domain.User) (UserUUIS.UUID, error) {
-	ur.logger.Info("UserRepo.CreateUser")
-
 	uuid := UserUUIS.NewUUID()
 	user.UUID = uuid.String()
+	ur.logger.DebugKV(ctx, eventUserCreate, "uuid", user.UUID)
 
 	const query = `INSERT INTO user (uuid, username, email) VALUES (?, ?, ?)`
 
 	_, err := ur.db.ExecContext(ctx, query, user.UUID, user.Name, user.Email)
 	if err != nil {
-		ur.logger.Error("UserRepo.CreateUser exec error", zap.Error(err))
+		if mysql.IsDuplicate(err) {
+			ur.logger.WarnKV(ctx, eventUserDuplicate, "uuid", user.UUID)
+			return "", err
+		}
+		ur.logger.ErrorKV(ctx, eventUserCreateFailed, "uuid", user.UUID, "error", err)
 		return "", err
 	}
 
-	ur.logger.Info("UserRepo.CreateUser: user created successfully", zap.String("UUID", uuid.String()))
-
+	ur.logger.InfoKV(ctx, eventUserCreated, "uuid", user.UUID)
 	return uuid, nil
 }
 
 func (ur *UserRepo) UpdateUser(ctx context.Context, user domain.User) (*domain.User, error) {
-	ur.logger.Info("UserRepo.UpdateUser", zap.String("uuid", user.UUID))
+	ur.logger.DebugKV(ctx, eventUserUpdate, "uuid", user.UUID)
 
 	query := `UPDATE user SET `
 	var args []any
@@ -95,47 +122,59 @@ func (ur *UserRepo) UpdateUser(ctx context.Context, user domain.User) (*domain.U
 
 	res, err := ur.db.ExecContext(ctx, query, args...)
 	if err != nil {
-		ur.logger.Error("UserRepo.UpdateUser exec error", zap.Error(err))
+		if mysql.IsDuplicate(err) {
+			ur.logger.WarnKV(ctx, eventUserDuplicate, "uuid", user.UUID)
+			return nil, err
+		}
+		ur.logger.ErrorKV(ctx, eventUserUpdateFailed, "uuid", user.UUID, "error", err)
 		return nil, err
 	}
 
 	affected, err := res.RowsAffected()
 	if err != nil {
-		ur.logger.Error("UserRepo.UpdateUser RowsAffected error", zap.Error(err))
+		ur.logger.ErrorKV(ctx, eventUserUpdateFailed, "uuid", user.UUID, "error", err)
 		return nil, err
 	}
 	if affected == 0 {
-		ur.logger.Error("UserRepo.UpdateUser no rows affected error", zap.Error(err))
+		ur.logger.WarnKV(ctx, eventUserNotFound, "uuid", user.UUID)
 		return nil, sql.ErrNoRows
 	}
 
-	ur.logger.Info("UserRepo.UpdateUser updated user successfully", zap.String("UUID", user.UUID))
-	return &user, nil
+	ur.logger.InfoKV(ctx, eventUserUpdated, "uuid", user.UUID)
+
+	// Read back from master, a replica may not have the update yet.
+	const read = `SELECT uuid, username, email FROM user WHERE uuid = ?`
+
+	var updated domain.User
+	if err := ur.db.Master().GetContext(ctx, &updated, read, user.UUID); err != nil {
+		ur.logger.ErrorKV(ctx, eventUserUpdateFailed, "uuid", user.UUID, "error", err)
+		return nil, err
+	}
+	return &updated, nil
 }
 
 func (ur *UserRepo) DeleteUser(ctx context.Context, UUID UserUUIS.UUID) error {
-	ur.logger.Info("UserRepo.DeleteUser", zap.String("UUID", UUID.String()))
+	ur.logger.DebugKV(ctx, eventUserDelete, "uuid", UUID.String())
 
 	const query = `DELETE FROM user WHERE uuid = ?`
 
 	res, err := ur.db.ExecContext(ctx, query, UUID.String())
 	if err != nil {
-		ur.logger.Error("UserRepo.DeleteUser exec error", zap.Error(err))
+		ur.logger.ErrorKV(ctx, eventUserDeleteFailed, "uuid", UUID.String(), "error", err)
 		return err
 	}
 
 	affected, err := res.RowsAffected()
 	if err != nil {
-		ur.logger.Error("UserRepo.DeleteUser RowsAffected error", zap.Error(err))
+		ur.logger.ErrorKV(ctx, eventUserDeleteFailed, "uuid", UUID.String(), "error", err)
 		return err
 	}
 
 	if affected == 0 {
-		ur.logger.Error("UserRepo.DeleteUser no rows affected error", zap.Error(err))
+		ur.logger.WarnKV(ctx, eventUserNotFound, "uuid", UUID.String())
 		return sql.ErrNoRows
 	}
 
-	ur.logger.Info("UserRepo.DeleteUser: user deleted successfully", zap.String("UUID", UUID.String()))
-
+	ur.logger.InfoKV(ctx, eventUserDeleted, "uuid", UUID.String())
 	return nil
 }
@@ -0,0 +1,233 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+
+	"example/internal/domain"
+	"example/internal/uuid"
+	"example/pkg/logger/loggertest"
+	"example/pkg/storage/mysql"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	mysqlDriver "github.com/go-sql-driver/mysql"
+	"github.com/jmoiron/sqlx"
+	"go.uber.org/zap/zapcore"
+)
+
+func newTestRepo(t *testing.T) (*UserRepo, sqlmock.Sqlmock, *loggertest.Logger) {
+	t.Helper()
+
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	if err != nil {
+		t.Fatalf("sqlmock: %v", err)
+	}
+	t.Cleanup(func() { _ = db.Close() })
+
+	log := loggertest.New()
+	return NewUserRepo(mysql.NewFromDB(sqlx.NewDb(db, "mysql")), log), mock, log
+}
+
+func userRows() *sqlmock.Rows {
+	return sqlmock.NewRows([]string{"uuid", "username", "email"})
+}
+
+func TestGetUserScansRow(t *testing.T) {
+	repo, mock, _ := newTestRepo(t)
+	id := uuid.NewUUID()
+
+	mock.ExpectQuery(`SELECT uuid, username, email FROM user WHERE uuid = ?`).
+		WithArgs(id.String()).
+		WillReturnRows(userRows().AddRow(id.String(), "alice", "alice@example.com"))
+
+	user, err := repo.GetUser(context.Background(), id)
+	if err != nil {
+		t.Fatalf("GetUser: %v", err)
+	}
+	if user == nil || user.UUID != id.String() || user.Name != "alice" || user.Email != "alice@example.com" {
+		t.Fatalf("GetUser = %+v", user)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestGetAllUsersScansRows(t *testing.T) {
+	repo, mock, _ := newTestRepo(t)
+
+	mock.ExpectQuery(`SELECT uuid, username, email FROM user`).
+		WillReturnRows(userRows().
+			AddRow("u1", "alice", "alice@example.com").
+			AddRow("u2", "bob", "bob@example.com"))
+
+	users, err := repo.GetAllUsers(context.Background())
+	if err != nil {
+		t.Fatalf("GetAllUsers: %v", err)
+	}
+	if len(users) != 2 || users[0].Name != "alice" || users[1].Name != "bob" {
+		t.Fatalf("GetAllUsers = %+v", users)
+	}
+}
+
+func TestUpdateUserReadsBackStoredRow(t *testing.T) {
+	repo, mock, _ := newTestRepo(t)
+	id := uuid.NewUUID()
+
+	mock.ExpectExec(`UPDATE user SET username = ? WHERE uuid = ?`).
+		WithArgs("alice", id.String()).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectQuery(`SELECT uuid, username, email FROM user WHERE uuid = ?`).
+		WithArgs(id.String()).
+		WillReturnRows(userRows().AddRow(id.String(), "alice", "alice@example.com"))
+
+	user, err := repo.UpdateUser(context.Background(), domain.User{UUID: id.String(), Name: "alice"})
+	if err != nil {
+		t.Fatalf("UpdateUser: %v", err)
+	}
+	if user.Name != "alice" || user.Email != "alice@example.com" {
+		t.Fatalf("UpdateUser = %+v", user)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// fakeDB is a mysql.MySQL returning the configured errors.
+type fakeDB struct {
+	getErr, selectErr, execErr error
+	affected                   int64
+}
+
+func (f *fakeDB) GetContext(context.Context, interface{}, string, ...interface{}) error {
+	return f.getErr
+}
+
+func (f *fakeDB) SelectContext(context.Context, interface{}, string, ...interface{}) error {
+	return f.selectErr
+}
+
+func (f *fakeDB) ExecContext(context.Context, string, ...interface{}) (sql.Result, error) {
+	if f.execErr != nil {
+		return nil, f.execErr
+	}
+	return sqlmock.NewResult(0, f.affected), nil
+}
+
+func (f *fakeDB) PrepareContext(context.Context, string) (*mysql.Stmt, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (f *fakeDB) Master() *mysql.Storage { return nil }
+func (f *fakeDB) Slave() *mysql.Storage  { return nil }
+
+func TestRepositoryLogging(t *testing.T) {
+	duplicate := &mysqlDriver.MySQLError{Number: 1062, Message: "Duplicate entry"}
+	id := uuid.NewUUID()
+
+	tests := []struct {
+		name  string
+		db    *fakeDB
+		call  func(*UserRepo) error
+		event string
+		level zapcore.Level
+	}{
+		{
+			name: "empty list",
+			db:   &fakeDB{},
+			call: func(r *UserRepo) error {
+				_, err := r.GetAllUsers(context.Background())
+				return err
+			},
+			event: eventUserList,
+			level: zapcore.DebugLevel,
+		},
+		{
+			name: "list failed",
+			db:   &fakeDB{selectErr: errors.New("connection refused")},
+			call: func(r *UserRepo) error {
+				_, err := r.GetAllUsers(context.Background())
+				return err
+			},
+			event: eventUserListFailed,
+			level: zapcore.ErrorLevel,
+		},
+		{
+			name: "get not found",
+			db:   &fakeDB{getErr: sql.ErrNoRows},
+			call: func(r *UserRepo) error {
+				_, err := r.GetUser(context.Background(), id)
+				return err
+			},
+			event: eventUserNotFound,
+			level: zapcore.WarnLevel,
+		},
+		{
+			name: "update not found",
+			db:   &fakeDB{},
+			call: func(r *UserRepo) error {
+				_, err := r.UpdateUser(context.Background(), domain.User{UUID: id.String(), Name: "a"})
+				return err
+			},
+			event: eventUserNotFound,
+			level: zapcore.WarnLevel,
+		},
+		{
+			name: "delete not found",
+			db:   &fakeDB{},
+			call: func(r *UserRepo) error {
+				return r.DeleteUser(context.Background(), id)
+			},
+			event: eventUserNotFound,
+			level: zapcore.WarnLevel,
+		},
+		{
+			name: "create duplicate",
+			db:   &fakeDB{execErr: duplicate},
+			call: func(r *UserRepo) error {
+				_, err := r.CreateUser(context.Background(), domain.User{Email: "a@example.com"})
+				return err
+			},
+			event: eventUserDuplicate,
+			level: zapcore.WarnLevel,
+		},
+		{
+			name: "update duplicate",
+			db:   &fakeDB{execErr: duplicate},
+			call: func(r *UserRepo) error {
+				_, err := r.UpdateUser(context.Background(), domain.User{UUID: id.String(), Email: "a@example.com"})
+				return err
+			},
+			event: eventUserDuplicate,
+			level: zapcore.WarnLevel,
+		},
+		{
+			name: "create failed",
+			db:   &fakeDB{execErr: errors.New("connection refused")},
+			call: func(r *UserRepo) error {
+				_, err := r.CreateUser(context.Background(), domain.User{})
+				return err
+			},
+			event: eventUserCreateFailed,
+			level: zapcore.ErrorLevel,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			log := loggertest.New()
+			_ = tt.call(NewUserRepo(tt.db, log))
+
+			entries := log.Events(tt.event)
+			if len(entries) != 1 || entries[0].Level != tt.level {
+				t.Fatalf("%s entries = %+v, want one at %s", tt.event, entries, tt.level)
+			}
+			if tt.level != zapcore.ErrorLevel {
+				if errs := log.AtLevel(zapcore.ErrorLevel); len(errs) != 0 {
+					t.Fatalf("unexpected errors logged: %+v", errs)
+				}
+			}
+		})
+	}
+}
@@ -6,4 +6,13 @@ type UUID string
 
 func NewUUID() UUID { return UUID(uuid.New().String()) }
 
+// Parse validates s and returns it in canonical form.
+func Parse(s string) (UUID, error) {
+	u, err := uuid.Parse(s)
+	if err != nil {
+		return "", err
+	}
+	return UUID(u.String()), nil
+}
+
 func (u UUID) String() string { return string(u) }
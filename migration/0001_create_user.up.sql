@@ -0,0 +1,8 @@
+CREATE TABLE IF NOT EXISTS user (
+    uuid     CHAR(36)     NOT NULL,
+    username VARCHAR(255) NOT NULL,
+    email    VARCHAR(255) NOT NULL,
+    PRIMARY KEY (uuid),
+    UNIQUE KEY ux_user_email (email)
+) ENGINE = InnoDB
+  DEFAULT CHARSET = utf8mb4;
@@ -0,0 +1,8 @@
+package migration
+
+import "embed"
+
+// FS holds the SQL migrations applied by mysql.New when migrations are enabled.
+//
+//go:embed *.sql
+var FS embed.FS
@@ -0,0 +1,15 @@
+package auth
+
+import "context"
+
+type contextKey struct{}
+
+func NewContext(ctx context.Context, c *Claims) context.Context {
+	return context.WithValue(ctx, contextKey{}, c)
+}
+
+// FromContext returns the claims put by Middleware, if the request was authenticated.
+func FromContext(ctx context.Context) (*Claims, bool) {
+	c, ok := ctx.Value(contextKey{}).(*Claims)
+	return c, ok
+}
@@ -0,0 +1,167 @@
+package auth
+
+import (
+	"crypto/rsa"
+	"slices"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+)
+
+type Claims struct {
+	jwt.RegisteredClaims
+	Roles []string `json:"roles,omitempty"`
+}
+
+func (c *Claims) HasRole(role string) bool {
+	return slices.Contains(c.Roles, role)
+}
+
+// JWT issues and verifies tokens signed with a single algorithm.
+type JWT struct {
+	method    jwt.SigningMethod
+	signKey   interface{}
+	verifyKey interface{}
+	// secret, if set, returns the HS256 key for every token instead of
+	// signKey and verifyKey.
+	secret func() []byte
+
+	issuer string
+	leeway time.Duration
+}
+
+type Option func(*JWT)
+
+// WithIssuer sets iss on issued tokens and requires it on verified ones.
+func WithIssuer(issuer string) Option {
+	return func(j *JWT) {
+		j.issuer = issuer
+	}
+}
+
+// WithLeeway allows for clock skew when checking exp, nbf and iat.
+func WithLeeway(d time.Duration) Option {
+	return func(j *JWT) {
+		j.leeway = d
+	}
+}
+
+// MinHS256SecretLen is the shortest secret NewHS256 accepts, the size of the
+// SHA-256 output as recommended by RFC 7518.
+const MinHS256SecretLen = 32
+
+func NewHS256(secret []byte, opts ...Option) (*JWT, error) {
+	if len(secret) < MinHS256SecretLen {
+		return nil, errors.Errorf("jwt: HS256 secret must be at least %d bytes, got %d", MinHS256SecretLen, len(secret))
+	}
+	return newJWT(jwt.SigningMethodHS256, secret, secret, opts), nil
+}
+
+// NewHS256Func creates an HS256 JWT that calls secret for every token it
+// issues or verifies, so a rotated secret is used without a restart. A secret
+// shorter than MinHS256SecretLen fails the token.
+func NewHS256Func(secret func() []byte, opts ...Option) (*JWT, error) {
+	if secret == nil {
+		return nil, errors.New("jwt: nil HS256 secret func")
+	}
+	j := newJWT(jwt.SigningMethodHS256, nil, nil, opts)
+	j.secret = secret
+	if _, _, err := j.keys(); err != nil {
+		return nil, err
+	}
+	return j, nil
+}
+
+// NewRS256 creates a JWT signing with private and verifying with public.
+// private may be nil for services that only verify tokens.
+func NewRS256(private *rsa.PrivateKey, public *rsa.PublicKey, opts ...Option) (*JWT, error) {
+	if public == nil && private != nil {
+		public = &private.PublicKey
+	}
+	if public == nil {
+		return nil, errors.New("jwt: RS256 public key is required")
+	}
+
+	var signKey interface{}
+	if private != nil {
+		signKey = private
+	}
+	return newJWT(jwt.SigningMethodRS256, signKey, public, opts), nil
+}
+
+func newJWT(method jwt.SigningMethod, signKey, verifyKey interface{}, opts []Option) *JWT {
+	j := &JWT{
+		method:    method,
+		signKey:   signKey,
+		verifyKey: verifyKey,
+	}
+	for i := range opts {
+		opts[i](j)
+	}
+	return j
+}
+
+// keys returns the current signing and verifying keys.
+func (j *JWT) keys() (signKey, verifyKey interface{}, err error) {
+	if j.secret == nil {
+		return j.signKey, j.verifyKey, nil
+	}
+	secret := j.secret()
+	if len(secret) < MinHS256SecretLen {
+		return nil, nil, errors.Errorf("jwt: HS256 secret must be at least %d bytes, got %d", MinHS256SecretLen, len(secret))
+	}
+	return secret, secret, nil
+}
+
+func (j *JWT) Issue(subject string, roles []string, ttl time.Duration) (string, error) {
+	signKey, _, err := j.keys()
+	if err != nil {
+		return "", err
+	}
+	if signKey == nil {
+		return "", errors.New("jwt: no signing key")
+	}
+
+	now := time.Now()
+	claims := Claims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        uuid.NewString(),
+			Issuer:    j.issuer,
+			Subject:   subject,
+			IssuedAt:  jwt.NewNumericDate(now),
+			NotBefore: jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(now.Add(ttl)),
+		},
+		Roles: roles,
+	}
+
+	token, err := jwt.NewWithClaims(j.method, claims).SignedString(signKey)
+	if err != nil {
+		return "", errors.Wrap(err, "jwt: sign")
+	}
+	return token, nil
+}
+
+func (j *JWT) Verify(token string) (*Claims, error) {
+	opts := []jwt.ParserOption{
+		jwt.WithValidMethods([]string{j.method.Alg()}),
+		jwt.WithExpirationRequired(),
+		jwt.WithIssuedAt(),
+		jwt.WithLeeway(j.leeway),
+	}
+	if j.issuer != "" {
+		opts = append(opts, jwt.WithIssuer(j.issuer))
+	}
+
+	var claims Claims
+	_, err := jwt.ParseWithClaims(token, &claims, func(*jwt.Token) (interface{}, error) {
+		_, verifyKey, err := j.keys()
+		return verifyKey, err
+	}, opts...)
+	if err != nil {
+		return nil, errors.Wrap(err, "jwt: verify")
+	}
+	return &claims, nil
+}
@@ -0,0 +1,152 @@
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/pkg/errors"
+)
+
+var testSecret = []byte(strings.Repeat("s", MinHS256SecretLen))
+
+func newTestHS256(t *testing.T, opts ...Option) *JWT {
+	t.Helper()
+	j, err := NewHS256(testSecret, opts...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return j
+}
+
+func testClaims(issuer string) Claims {
+	now := time.Now()
+	return Claims{RegisteredClaims: jwt.RegisteredClaims{
+		Issuer:    issuer,
+		Subject:   "user",
+		IssuedAt:  jwt.NewNumericDate(now),
+		ExpiresAt: jwt.NewNumericDate(now.Add(time.Hour)),
+	}}
+}
+
+func TestNewHS256RejectsShortSecrets(t *testing.T) {
+	for _, secret := range [][]byte{nil, []byte("SECRET"), testSecret[1:]} {
+		if _, err := NewHS256(secret); err == nil {
+			t.Errorf("NewHS256 accepted a %d byte secret", len(secret))
+		}
+	}
+	if _, err := NewHS256(testSecret); err != nil {
+		t.Errorf("NewHS256: %v", err)
+	}
+}
+
+func TestVerify(t *testing.T) {
+	j := newTestHS256(t, WithIssuer("example"))
+
+	valid, err := j.Issue("user", []string{"admin"}, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	claims, err := j.Verify(valid)
+	if err != nil {
+		t.Fatalf("Verify: %v", err)
+	}
+	if claims.Subject != "user" || !claims.HasRole("admin") {
+		t.Fatalf("claims = %+v", claims)
+	}
+
+	expired, err := j.Issue("user", nil, -time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	wrongIssuer, err := newTestHS256(t, WithIssuer("other")).Issue("user", nil, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	none, err := jwt.NewWithClaims(jwt.SigningMethodNone, testClaims("example")).
+		SignedString(jwt.UnsafeAllowNoneSignatureType)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rs256, err := jwt.NewWithClaims(jwt.SigningMethodRS256, testClaims("example")).SignedString(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	otherSecret, err := NewHS256([]byte(strings.Repeat("o", MinHS256SecretLen)), WithIssuer("example"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	wrongKey, err := otherSecret.Issue("user", nil, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name  string
+		token string
+		want  error
+	}{
+		{"expired", expired, jwt.ErrTokenExpired},
+		{"wrong issuer", wrongIssuer, jwt.ErrTokenInvalidIssuer},
+		{"alg none", none, jwt.ErrTokenSignatureInvalid},
+		{"RS256", rs256, jwt.ErrTokenSignatureInvalid},
+		{"wrong key", wrongKey, jwt.ErrTokenSignatureInvalid},
+		{"empty", "", jwt.ErrTokenMalformed},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			claims, err := j.Verify(tt.token)
+			if err == nil {
+				t.Fatalf("Verify accepted the token: %+v", claims)
+			}
+			if !errors.Is(err, tt.want) {
+				t.Fatalf("Verify error = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestHS256FuncFollowsRotation(t *testing.T) {
+	secret := testSecret
+	j, err := NewHS256Func(func() []byte { return secret })
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	old, err := j.Issue("user", nil, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	secret = []byte(strings.Repeat("r", MinHS256SecretLen))
+	if _, err := j.Verify(old); !errors.Is(err, jwt.ErrTokenSignatureInvalid) {
+		t.Fatalf("Verify with the rotated secret = %v, want signature invalid", err)
+	}
+	rotated, err := j.Issue("user", nil, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := j.Verify(rotated); err != nil {
+		t.Fatalf("Verify: %v", err)
+	}
+
+	secret = []byte("short")
+	if _, err := j.Verify(rotated); err == nil {
+		t.Fatal("Verify accepted a token with a short secret")
+	}
+	if _, err := NewHS256Func(func() []byte { return nil }); err == nil {
+		t.Fatal("NewHS256Func accepted an empty secret")
+	}
+}
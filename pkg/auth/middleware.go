@@ -0,0 +1,115 @@
+package auth
+
+import (
+	"net/http"
+	"path"
+	"strings"
+
+	"example/pkg/logger"
+	"example/pkg/server"
+
+	"github.com/pkg/errors"
+)
+
+var (
+	errNoToken      = errors.New("missing bearer token")
+	errInvalidToken = errors.New("invalid token")
+	errForbidden    = errors.New("forbidden")
+)
+
+type Verifier interface {
+	Verify(token string) (*Claims, error)
+}
+
+type middlewareOptions struct {
+	publicPaths []string
+}
+
+type MiddlewareOption func(*middlewareOptions)
+
+// WithPublicPaths lets requests to one of prefixes, or below it, through
+// without a token. Prefixes match whole path segments.
+func WithPublicPaths(prefixes ...string) MiddlewareOption {
+	return func(o *middlewareOptions) {
+		o.publicPaths = append(o.publicPaths, prefixes...)
+	}
+}
+
+// Middleware authenticates requests with a bearer token and puts its claims
+// into the request context. It can be plugged in with mwhttp.WithCustomMiddleware.
+func Middleware(v Verifier, opts ...MiddlewareOption) func(http.Handler) http.Handler {
+	o := &middlewareOptions{}
+	for i := range opts {
+		opts[i](o)
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Method == http.MethodOptions || o.isPublic(r.URL.Path) {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			token, ok := bearerToken(r)
+			if !ok {
+				unauthorized(w, r, errNoToken)
+				return
+			}
+
+			claims, err := v.Verify(token)
+			if err != nil {
+				unauthorized(w, r, errInvalidToken)
+				return
+			}
+
+			ctx := logger.AddFields(NewContext(r.Context(), claims), "subject", claims.Subject)
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+// RequireRole rejects authenticated requests whose claims lack role with 403.
+func RequireRole(role string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			claims, ok := FromContext(r.Context())
+			if !ok {
+				unauthorized(w, r, errNoToken)
+				return
+			}
+			if !claims.HasRole(role) {
+				server.ErrorJSON(w, r, http.StatusForbidden, errForbidden)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// isPublic reports whether p is a public path or below one. Paths match by
+// whole segments: /api/status covers /api/status/db but not /api/statusX.
+func (o *middlewareOptions) isPublic(p string) bool {
+	p = path.Clean("/" + p)
+	for _, public := range o.publicPaths {
+		public = strings.TrimSuffix(path.Clean("/"+public), "/")
+		if p == public || strings.HasPrefix(p, public+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+func bearerToken(r *http.Request) (string, bool) {
+	h := r.Header.Get("Authorization")
+	scheme, token, ok := strings.Cut(h, " ")
+	if !ok || !strings.EqualFold(scheme, "Bearer") {
+		return "", false
+	}
+	token = strings.TrimSpace(token)
+	return token, token != ""
+}
+
+func unauthorized(w http.ResponseWriter, r *http.Request, err error) {
+	w.Header().Set("WWW-Authenticate", `Bearer`)
+	server.ErrorJSON(w, r, http.StatusUnauthorized, err)
+}
@@ -0,0 +1,129 @@
+package auth
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"example/pkg/logger"
+)
+
+func TestMiddlewareUnauthorized(t *testing.T) {
+	j := newTestHS256(t)
+	expired, err := j.Issue("user", nil, -time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	h := Middleware(j)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Error("handler called without a valid token")
+	}))
+
+	tests := []struct {
+		name    string
+		header  string
+		message string
+	}{
+		{"missing", "", errNoToken.Error()},
+		{"not bearer", "Basic dXNlcjpwYXNz", errNoToken.Error()},
+		{"empty bearer", "Bearer ", errNoToken.Error()},
+		{"expired", "Bearer " + expired, errInvalidToken.Error()},
+		{"garbage", "Bearer not-a-token", errInvalidToken.Error()},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, "/api/v1/users", nil)
+			if tt.header != "" {
+				r.Header.Set("Authorization", tt.header)
+			}
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, r)
+
+			if w.Code != http.StatusUnauthorized {
+				t.Fatalf("status = %d, want 401", w.Code)
+			}
+			if got := w.Header().Get("WWW-Authenticate"); got != "Bearer" {
+				t.Errorf("WWW-Authenticate = %q", got)
+			}
+
+			var body struct {
+				Meta struct {
+					Message string `json:"message"`
+					Code    int    `json:"code"`
+				} `json:"meta"`
+			}
+			if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+				t.Fatalf("body %q: %v", w.Body.String(), err)
+			}
+			if body.Meta.Code != http.StatusUnauthorized || body.Meta.Message != tt.message {
+				t.Fatalf("body = %s", w.Body.String())
+			}
+		})
+	}
+}
+
+func TestMiddlewarePassesClaims(t *testing.T) {
+	j := newTestHS256(t)
+	token, err := j.Issue("user", nil, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var subject string
+	h := Middleware(j)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if c, ok := FromContext(r.Context()); ok {
+			subject = c.Subject
+		}
+	}))
+
+	// The access log holds the context from before the middleware ran.
+	outer := logger.WithFieldBag(context.Background())
+	r := httptest.NewRequest(http.MethodGet, "/api/v1/users", nil).WithContext(outer)
+	r.Header.Set("Authorization", "Bearer "+token)
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+
+	if w.Code != http.StatusOK || subject != "user" {
+		t.Fatalf("status = %d, subject = %q", w.Code, subject)
+	}
+	if got := logger.Fields(outer)["subject"]; got != "user" {
+		t.Fatalf("subject in the outer log fields = %v", got)
+	}
+}
+
+func TestMiddlewarePublicPaths(t *testing.T) {
+	h := Middleware(newTestHS256(t), WithPublicPaths("/api/status", "/swagger/"))(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}),
+	)
+
+	tests := []struct {
+		path   string
+		public bool
+	}{
+		{"/api/status", true},
+		{"/api/status/", true},
+		{"/api/status/db", true},
+		{"/swagger", true},
+		{"/swagger/index.html", true},
+		{"/api/statusX", false},
+		{"/api/status-admin", false},
+		{"/api/status/../v1/users", false},
+		{"/swaggerx", false},
+		{"/api/v1/users", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, tt.path, nil))
+
+			if got := w.Code == http.StatusOK; got != tt.public {
+				t.Fatalf("%s public = %v, want %v (status %d)", tt.path, got, tt.public, w.Code)
+			}
+		})
+	}
+}
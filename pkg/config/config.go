@@ -1,12 +1,9 @@
 package config
 
 import (
-	"fmt"
-	"github.com/pkg/errors"
-	"github.com/spf13/viper"
 	"os"
-	"reflect"
 	"strings"
+	"time"
 )
 
 const (
@@ -15,20 +12,29 @@ const (
 	configJSON = "json"
 )
 
-type SecretString string
-
-func (s SecretString) String() string { return "*********" }
-func (s SecretString) Value() string  { return string(s) }
-
+// Options selects the config sources. Later sources override earlier ones:
+// file, then Consul, then Vault, then environment variables.
 type Options struct {
-	Dir                string
-	File               string
+	Dir  string
+	File string
+	// Format is one of json, yaml, toml or dotenv. It is detected from the
+	// file extension when empty.
+	Format string
+	// Overlays are files in Dir merged on top of File in order.
+	Overlays []string
+	// DotEnv files provide env vars to this Loader only, the process
+	// environment is not modified. Variables set in the environment win.
+	DotEnv             []string
+	Consul             *ConsulOptions
+	Vault              *VaultOptions
 	ReplaceFromEnvVars bool
+	// ReloadInterval makes a Watcher poll Consul and Vault for changes.
+	ReloadInterval time.Duration
 }
 
 func (o *Options) Fill() {
 	if o.File == "" {
-		o.File = configPath + "." + configJSON
+		o.File = configName + "." + configJSON
 	}
 	if o.Dir == "" {
 		o.Dir = configPath
@@ -38,37 +44,7 @@ func (o *Options) Fill() {
 	}
 }
 
+// NewConfig loads cfg with a one-off Loader.
 func NewConfig(cfg interface{}, opts Options) error {
-	t := reflect.TypeOf(cfg)
-	if t.Kind() != reflect.Ptr {
-		return fmt.Errorf("cfg must be a pointer")
-	}
-	if t.Elem().Kind() != reflect.Struct {
-		return fmt.Errorf("cfg arg must be a struct")
-	}
-
-	opts.Fill()
-	envParams()
-
-	fileOk, err := fromFile(&opts)
-	if err != nil {
-		return errors.Wrap(err, "fromfile")
-	}
-
-	if !fileOk {
-		return errors.New("cannot load from config, please set at least one of sources: file, consul, vault")
-	}
-
-	if opts.ReplaceFromEnvVars {
-		viper.SetEnvKeyReplacer(strings.NewReplacer(".", "_"))
-		viper.AllowEmptyEnv(true)
-		viper.AutomaticEnv()
-	}
-
-	err = viper.Unmarshal(cfg)
-	if err != nil {
-		return errors.Wrap(err, "unmarshal config")
-	}
-
-	return nil
+	return NewLoader(opts).Load(cfg)
 }
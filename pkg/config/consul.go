@@ -0,0 +1,71 @@
+package config
+
+import (
+	"context"
+	"encoding/json"
+
+	"example/pkg/logger"
+
+	consul "github.com/hashicorp/consul/api"
+	"github.com/pkg/errors"
+	"github.com/spf13/viper"
+)
+
+// ConsulOptions points at a Consul KV key holding a JSON document with the
+// same layout as the config file. Empty fields fall back to the CONSUL_HTTP_*
+// environment variables.
+type ConsulOptions struct {
+	Address    string
+	Token      string
+	Datacenter string
+	// Key defaults to <AppName>/<EnvName>.
+	Key string
+}
+
+func fromConsul(v *viper.Viper, opts *Options, names EnvNames, o *origins) (bool, error) {
+	if opts.Consul == nil {
+		return false, nil
+	}
+
+	c := consul.DefaultConfig()
+	if opts.Consul.Address != "" {
+		c.Address = opts.Consul.Address
+	}
+	if opts.Consul.Token != "" {
+		c.Token = opts.Consul.Token
+	}
+	if opts.Consul.Datacenter != "" {
+		c.Datacenter = opts.Consul.Datacenter
+	}
+
+	client, err := consul.NewClient(c)
+	if err != nil {
+		return false, errors.Wrap(err, "failed to create consul client")
+	}
+
+	key := opts.Consul.Key
+	if key == "" {
+		key = names.AppName + "/" + names.EnvName
+	}
+
+	pair, _, err := client.KV().Get(key, nil)
+	if err != nil {
+		return false, errors.Wrapf(err, "failed to read consul key %s", key)
+	}
+	if pair == nil {
+		return false, errors.Errorf("consul key %s not found", key)
+	}
+
+	var values map[string]interface{}
+	if err := json.Unmarshal(pair.Value, &values); err != nil {
+		return false, errors.Wrapf(err, "failed to parse consul key %s", key)
+	}
+
+	if err := v.MergeConfigMap(values); err != nil {
+		return false, errors.Wrap(err, "failed to merge consul config")
+	}
+	o.set(flattenKeys(values, ""), "consul:"+key)
+	logger.Debugf(context.Background(), "Config: load from consul (%s)", key)
+
+	return true, nil
+}
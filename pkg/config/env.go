@@ -2,6 +2,8 @@ package config
 
 import "os"
 
+// AppName, EnvName and HostName come from the process environment. A Loader
+// also reads them from its .env files, see Loader.EnvNames.
 var (
 	AppName = "unknown"
 	EnvName = "unknown"
@@ -15,21 +17,39 @@ const (
 	hostNameKey = "HOSTNAME"
 )
 
-func envParams() {
-	if appName, ok := os.LookupEnv(appNameKey); ok && appName != "" {
-		AppName = appName
+func init() {
+	n := envParams(nil)
+	AppName, EnvName, HostName = n.AppName, n.EnvName, n.HostName
+}
+
+// EnvNames name the running app. They select the env overlay file and the
+// default Consul key and Vault path.
+type EnvNames struct {
+	AppName  string
+	EnvName  string
+	HostName string
+}
+
+// envParams resolves the names from the environment or the variables read
+// from .env files.
+func envParams(dotEnv map[string]string) EnvNames {
+	n := EnvNames{AppName: "unknown", EnvName: "unknown", HostName: "unknown"}
+
+	if appName, ok := lookupEnv(dotEnv, appNameKey); ok && appName != "" {
+		n.AppName = appName
 	}
 
-	if envName, ok := os.LookupEnv(envNameKey); ok && envName != "" {
-		EnvName = envName
+	if envName, ok := lookupEnv(dotEnv, envNameKey); ok && envName != "" {
+		n.EnvName = envName
 	}
 
-	hostName := os.Getenv(hostNameKey)
+	hostName, _ := lookupEnv(dotEnv, hostNameKey)
 	if hostName == "" {
 		hostName, _ = os.Hostname()
 	}
 
 	if hostName != "" {
-		HostName = hostName
+		n.HostName = hostName
 	}
+	return n
 }
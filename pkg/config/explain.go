@@ -0,0 +1,148 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Entry is one effective config value and the source it came from.
+type Entry struct {
+	Key    string `json:"key"`
+	Value  string `json:"value"`
+	Source string `json:"source,omitempty"`
+}
+
+// origins remembers which source set each key last.
+type origins struct {
+	keys map[string]string
+	// dotEnv maps env vars read from .env files to the file.
+	dotEnv map[string]string
+	env    bool
+}
+
+func newOrigins() *origins {
+	return &origins{
+		keys:   make(map[string]string),
+		dotEnv: make(map[string]string),
+	}
+}
+
+func (o *origins) set(keys []string, source string) {
+	for _, k := range keys {
+		o.keys[strings.ToLower(k)] = source
+	}
+}
+
+func (o *origins) source(key string, t reflect.StructField) string {
+	if o.env {
+		name := strings.ToUpper(envReplacer.Replace(key))
+		if _, ok := os.LookupEnv(name); ok {
+			return "env:" + name
+		}
+		if file, ok := o.dotEnv[name]; ok {
+			return "env:" + name + " (" + file + ")"
+		}
+	}
+
+	// A source may set a whole subtree, e.g. a slice of structs.
+	for k := strings.ToLower(key); k != ""; k = parentKey(k) {
+		if src, ok := o.keys[k]; ok {
+			return src
+		}
+	}
+
+	if _, ok := t.Tag.Lookup(defaultTag); ok {
+		return "default:tag"
+	}
+	return "default"
+}
+
+func parentKey(key string) string {
+	if i := strings.LastIndexAny(key, ".["); i > 0 {
+		return key[:i]
+	}
+	return ""
+}
+
+// Explain loads cfg like Load and lists every leaf key with its effective
+// value and source: a file, consul, vault, an env var, a default tag or the
+// value cfg held before loading. Secrets are printed with their String
+// method. A ValidationError is returned together with the entries.
+func (l *Loader) Explain(cfg interface{}) ([]Entry, error) {
+	o, err := l.loadWithOrigins("", cfg)
+	if o == nil {
+		return nil, err
+	}
+
+	var entries []Entry
+	explainValue(reflect.ValueOf(cfg), reflect.StructField{}, "", o, &entries)
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Key < entries[j].Key })
+
+	return entries, err
+}
+
+func explainValue(v reflect.Value, f reflect.StructField, key string, o *origins, entries *[]Entry) {
+	if v.Kind() == reflect.Ptr && !v.IsNil() && v.Elem().Kind() == reflect.Struct {
+		v = v.Elem()
+	}
+
+	switch {
+	case v.Kind() == reflect.Struct && !isLeaf(v.Type()):
+		t := v.Type()
+		for i := 0; i < t.NumField(); i++ {
+			sf := t.Field(i)
+			if !sf.IsExported() {
+				continue
+			}
+			k, _ := fieldKey(sf, key)
+			explainValue(v.Field(i), sf, k, o, entries)
+		}
+	case v.Kind() == reflect.Slice && v.Type().Elem().Kind() == reflect.Struct && v.Len() > 0:
+		for i := 0; i < v.Len(); i++ {
+			explainValue(v.Index(i), f, fmt.Sprintf("%s[%d]", key, i), o, entries)
+		}
+	default:
+		*entries = append(*entries, Entry{
+			Key:    key,
+			Value:  formatValue(v),
+			Source: o.source(key, f),
+		})
+	}
+}
+
+func isLeaf(t reflect.Type) bool {
+	return t == reflect.TypeOf(time.Time{}) || t == secretType
+}
+
+func formatValue(v reflect.Value) string {
+	if v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return "<nil>"
+		}
+		v = v.Elem()
+	}
+	if s, ok := v.Interface().(fmt.Stringer); ok {
+		return s.String()
+	}
+	return fmt.Sprint(v.Interface())
+}
+
+func flattenKeys(m map[string]interface{}, prefix string) []string {
+	var keys []string
+	for k, v := range m {
+		key := k
+		if prefix != "" {
+			key = prefix + "." + k
+		}
+		if nested, ok := v.(map[string]interface{}); ok {
+			keys = append(keys, flattenKeys(nested, key)...)
+			continue
+		}
+		keys = append(keys, key)
+	}
+	return keys
+}
@@ -3,19 +3,121 @@ package config
 import (
 	"context"
 	"example/pkg/logger"
+	"os"
+	"path/filepath"
+	"strings"
+
 	"github.com/pkg/errors"
 	"github.com/spf13/viper"
+	"github.com/subosito/gotenv"
+)
+
+const (
+	configYAML   = "yaml"
+	configTOML   = "toml"
+	configDotEnv = "dotenv"
 )
 
-func fromFile(opts *Options) (bool, error) {
-	viper.SetConfigType(configJSON)
-	viper.AddConfigPath(opts.Dir)
-	viper.SetConfigName(opts.File)
+var formats = map[string]string{
+	".json": configJSON,
+	".yaml": configYAML,
+	".yml":  configYAML,
+	".toml": configTOML,
+	".env":  configDotEnv,
+}
+
+// detectFormat returns Options.Format if set, otherwise the format matching
+// the extension of file.
+func detectFormat(opts *Options, file string) (string, error) {
+	if opts.Format != "" {
+		return opts.Format, nil
+	}
+	if f, ok := formats[strings.ToLower(filepath.Ext(file))]; ok {
+		return f, nil
+	}
+	return "", errors.Errorf("can't detect the format of %s, set Options.Format", file)
+}
+
+// configFiles returns the base file followed by the overlays merged on top of
+// it: Options.Overlays and, if it exists, <name>.<EnvName>.<ext>, e.g.
+// values.local.yaml for values.yaml.
+func configFiles(opts *Options, names EnvNames) []string {
+	base := filepath.Join(opts.Dir, opts.File)
+	files := []string{base}
+
+	for _, o := range opts.Overlays {
+		files = append(files, filepath.Join(opts.Dir, o))
+	}
+
+	ext := filepath.Ext(opts.File)
+	envOverlay := filepath.Join(opts.Dir, strings.TrimSuffix(opts.File, ext)+"."+names.EnvName+ext)
+	if _, err := os.Stat(envOverlay); err == nil {
+		files = append(files, envOverlay)
+	}
+
+	return files
+}
+
+func fromFile(v *viper.Viper, opts *Options, names EnvNames, o *origins) (bool, error) {
+	files := configFiles(opts, names)
+
+	if _, err := os.Stat(files[0]); errors.Is(err, os.ErrNotExist) && (opts.Consul != nil || opts.Vault != nil) {
+		// The file is optional when the config comes from a remote source.
+		return false, nil
+	}
 
-	if err := viper.ReadInConfig(); err != nil {
-		return false, errors.Wrap(err, "failed to read the configuration file")
-		logger.Debugf(context.Background(), "Config: load from file (%s)", opts.File)
+	for _, file := range files {
+		format, err := detectFormat(opts, file)
+		if err != nil {
+			return false, err
+		}
+		fv := viper.New()
+		fv.SetConfigType(format)
+		fv.SetConfigFile(file)
+		if err := fv.ReadInConfig(); err != nil {
+			return false, errors.Wrapf(err, "failed to read the configuration file %s", file)
+		}
+		if err := v.MergeConfigMap(fv.AllSettings()); err != nil {
+			return false, errors.Wrapf(err, "failed to merge the configuration file %s", file)
+		}
+		o.set(fv.AllKeys(), "file:"+file)
+		logger.Debugf(context.Background(), "Config: load from file (%s)", file)
 	}
 
 	return true, nil
 }
+
+// loadDotEnv reads variables from Options.DotEnv files, the first file
+// setting a variable wins. The process environment is left untouched, so
+// every load sees the current content of the files. Missing files are skipped.
+func loadDotEnv(opts *Options, o *origins) (map[string]string, error) {
+	vars := make(map[string]string)
+	for _, file := range opts.DotEnv {
+		if _, err := os.Stat(file); errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+		env, err := gotenv.Read(file)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to load %s", file)
+		}
+		for k, val := range env {
+			if _, ok := vars[k]; ok {
+				continue
+			}
+			vars[k] = val
+			o.dotEnv[k] = file
+		}
+		logger.Debugf(context.Background(), "Config: load env from file (%s)", file)
+	}
+	return vars, nil
+}
+
+// lookupEnv returns the value of the env var name, falling back to the
+// variables read from .env files.
+func lookupEnv(dotEnv map[string]string, name string) (string, bool) {
+	if val, ok := os.LookupEnv(name); ok {
+		return val, true
+	}
+	val, ok := dotEnv[name]
+	return val, ok
+}
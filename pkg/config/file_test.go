@@ -0,0 +1,124 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+type dotEnvConfig struct {
+	DB struct {
+		Host string `mapstructure:"host"`
+		Port int    `mapstructure:"port"`
+	} `mapstructure:"db"`
+}
+
+func writeFile(t *testing.T, name, content string) {
+	t.Helper()
+	if err := os.WriteFile(name, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestDotEnvStaysInLoader(t *testing.T) {
+	dir := t.TempDir()
+	dotEnv := filepath.Join(dir, ".env")
+	writeFile(t, filepath.Join(dir, "values.json"), `{"db": {"host": "file", "port": 1}}`)
+	writeFile(t, dotEnv, "DB_HOST=dotenv\n")
+
+	l := NewLoader(Options{Dir: dir, DotEnv: []string{dotEnv}, ReplaceFromEnvVars: true})
+
+	var cfg dotEnvConfig
+	if err := l.Load(&cfg); err != nil {
+		t.Fatal(err)
+	}
+	if cfg.DB.Host != "dotenv" || cfg.DB.Port != 1 {
+		t.Fatalf("Load = %+v", cfg.DB)
+	}
+	if _, ok := os.LookupEnv("DB_HOST"); ok {
+		t.Fatal("DB_HOST leaked into the process environment")
+	}
+
+	// Another Loader without the .env file must not see its values.
+	var other dotEnvConfig
+	if err := NewLoader(Options{Dir: dir, ReplaceFromEnvVars: true}).Load(&other); err != nil {
+		t.Fatal(err)
+	}
+	if other.DB.Host != "file" {
+		t.Fatalf("other Loader DB.Host = %q", other.DB.Host)
+	}
+
+	// Edits are picked up by the next load.
+	writeFile(t, dotEnv, "DB_HOST=edited\n")
+	if err := l.Load(&cfg); err != nil {
+		t.Fatal(err)
+	}
+	if cfg.DB.Host != "edited" {
+		t.Fatalf("reload DB.Host = %q", cfg.DB.Host)
+	}
+}
+
+func TestDotEnvLosesToEnvironment(t *testing.T) {
+	dir := t.TempDir()
+	dotEnv := filepath.Join(dir, ".env")
+	writeFile(t, filepath.Join(dir, "values.json"), `{"db": {"host": "file"}}`)
+	writeFile(t, dotEnv, "DB_HOST=dotenv\nDB_PORT=2\n")
+	t.Setenv("DB_HOST", "env")
+
+	var cfg dotEnvConfig
+	l := NewLoader(Options{Dir: dir, DotEnv: []string{dotEnv}, ReplaceFromEnvVars: true})
+	entries, err := l.Explain(&cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.DB.Host != "env" || cfg.DB.Port != 2 {
+		t.Fatalf("Load = %+v", cfg.DB)
+	}
+
+	sources := make(map[string]string)
+	for _, e := range entries {
+		sources[e.Key] = e.Source
+	}
+	if got := sources["db.host"]; got != "env:DB_HOST" {
+		t.Errorf("db.host source = %q", got)
+	}
+	if got, want := sources["db.port"], "env:DB_PORT ("+dotEnv+")"; got != want {
+		t.Errorf("db.port source = %q, want %q", got, want)
+	}
+}
+
+func TestEnvNamesPerLoader(t *testing.T) {
+	if _, ok := os.LookupEnv(envNameKey); ok {
+		t.Skipf("%s is set in the environment", envNameKey)
+	}
+	dir := t.TempDir()
+	dotEnv := filepath.Join(dir, ".env")
+	writeFile(t, filepath.Join(dir, "values.json"), `{"db": {"host": "base"}}`)
+	writeFile(t, filepath.Join(dir, "values.staging.json"), `{"db": {"host": "staging"}}`)
+	writeFile(t, dotEnv, envNameKey+"=staging\n")
+
+	staging := NewLoader(Options{Dir: dir, DotEnv: []string{dotEnv}, ReplaceFromEnvVars: true})
+	var cfg dotEnvConfig
+	if err := staging.Load(&cfg); err != nil {
+		t.Fatal(err)
+	}
+	if cfg.DB.Host != "staging" {
+		t.Fatalf("DB.Host = %q, want the staging overlay", cfg.DB.Host)
+	}
+	if names, err := staging.EnvNames(); err != nil || names.EnvName != "staging" {
+		t.Fatalf("EnvNames = %+v, %v", names, err)
+	}
+
+	// The first Loader's .env must not pick the overlay of later ones.
+	other := NewLoader(Options{Dir: dir, ReplaceFromEnvVars: true})
+	var otherCfg dotEnvConfig
+	if err := other.Load(&otherCfg); err != nil {
+		t.Fatal(err)
+	}
+	if otherCfg.DB.Host != "base" {
+		t.Fatalf("other Loader DB.Host = %q, want base", otherCfg.DB.Host)
+	}
+	if names, _ := other.EnvNames(); names.EnvName != "unknown" || EnvName != "unknown" {
+		t.Fatalf("other Loader EnvNames = %+v, EnvName = %q", names, EnvName)
+	}
+}
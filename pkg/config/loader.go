@@ -0,0 +1,181 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"reflect"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/viper"
+)
+
+var envReplacer = strings.NewReplacer(".", "_")
+
+// Loader reads config sources into its own viper instance, so several
+// configs can be loaded in one process, concurrently if needed.
+type Loader struct {
+	opts Options
+}
+
+func NewLoader(opts Options) *Loader {
+	opts.Fill()
+	return &Loader{opts: opts}
+}
+
+// Load reads all sources and unmarshals the whole config into cfg, which must
+// be a pointer to a struct.
+func (l *Loader) Load(cfg interface{}) error {
+	return l.load("", cfg)
+}
+
+// LoadSub unmarshals only the subtree under key, e.g. "db", into cfg. Defaults
+// and validation errors are reported with the full key path.
+func (l *Loader) LoadSub(key string, cfg interface{}) error {
+	if key == "" {
+		return errors.New("key must not be empty")
+	}
+	return l.load(key, cfg)
+}
+
+// EnvNames resolves the app names from the environment and, with
+// ReplaceFromEnvVars, the .env files of the Loader. Other Loaders don't see
+// them.
+func (l *Loader) EnvNames() (EnvNames, error) {
+	dotEnv, err := l.dotEnv(newOrigins())
+	if err != nil {
+		return EnvNames{}, err
+	}
+	return envParams(dotEnv), nil
+}
+
+func (l *Loader) dotEnv(o *origins) (map[string]string, error) {
+	if !l.opts.ReplaceFromEnvVars {
+		return nil, nil
+	}
+	dotEnv, err := loadDotEnv(&l.opts, o)
+	if err != nil {
+		return nil, errors.Wrap(err, "fromdotenv")
+	}
+	return dotEnv, nil
+}
+
+func (l *Loader) load(key string, cfg interface{}) error {
+	_, err := l.loadWithOrigins(key, cfg)
+	return err
+}
+
+func (l *Loader) loadWithOrigins(key string, cfg interface{}) (*origins, error) {
+	t := reflect.TypeOf(cfg)
+	if t == nil || t.Kind() != reflect.Ptr {
+		return nil, fmt.Errorf("cfg must be a pointer")
+	}
+	if t.Elem().Kind() != reflect.Struct {
+		return nil, fmt.Errorf("cfg arg must be a struct")
+	}
+
+	o := newOrigins()
+	dotEnv, err := l.dotEnv(o)
+	if err != nil {
+		return nil, err
+	}
+
+	v, err := l.read(t, key, o, dotEnv)
+	if err != nil {
+		return nil, err
+	}
+
+	if key != "" {
+		sub := viper.New()
+		if err := sub.MergeConfigMap(subtree(v.AllSettings(), key)); err != nil {
+			return nil, errors.Wrapf(err, "config key %s", key)
+		}
+		v = sub
+	}
+
+	if err := v.Unmarshal(cfg, viper.DecodeHook(decodeHook())); err != nil {
+		return nil, errors.Wrap(err, "unmarshal config")
+	}
+
+	return o, validate(reflect.ValueOf(cfg), key)
+}
+
+func (l *Loader) read(t reflect.Type, prefix string, o *origins, dotEnv map[string]string) (*viper.Viper, error) {
+	opts := l.opts
+	v := viper.New()
+	setDefaults(v, t, prefix)
+	names := envParams(dotEnv)
+
+	fileOk, err := fromFile(v, &opts, names, o)
+	if err != nil {
+		return nil, errors.Wrap(err, "fromfile")
+	}
+
+	consulOk, err := fromConsul(v, &opts, names, o)
+	if err != nil {
+		return nil, errors.Wrap(err, "fromconsul")
+	}
+
+	vaultOk, err := fromVault(v, &opts, names, o)
+	if err != nil {
+		return nil, errors.Wrap(err, "fromvault")
+	}
+
+	if !fileOk && !consulOk && !vaultOk {
+		return nil, errors.New("cannot load from config, please set at least one of sources: file, consul, vault")
+	}
+
+	if opts.ReplaceFromEnvVars {
+		v.SetEnvKeyReplacer(envReplacer)
+		v.AllowEmptyEnv(true)
+		v.AutomaticEnv()
+		// AutomaticEnv only sees keys present in some source, bind the rest
+		// so e.g. DB_DB_PASSWORD works without a password key in the file.
+		bindEnvs(v, t, prefix, dotEnv)
+		o.env = true
+	}
+
+	return v, nil
+}
+
+func subtree(settings map[string]interface{}, key string) map[string]interface{} {
+	m := settings
+	for _, part := range strings.Split(strings.ToLower(key), ".") {
+		next, ok := m[part].(map[string]interface{})
+		if !ok {
+			return map[string]interface{}{}
+		}
+		m = next
+	}
+	return m
+}
+
+// bindEnvs binds every leaf key of t to its env var. Variables only found in
+// .env files are set as overrides, they rank like env vars.
+func bindEnvs(v *viper.Viper, t reflect.Type, prefix string, dotEnv map[string]string) {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct || isLeaf(t) {
+		if prefix == "" {
+			return
+		}
+		_ = v.BindEnv(prefix)
+		name := strings.ToUpper(envReplacer.Replace(prefix))
+		if _, ok := os.LookupEnv(name); !ok {
+			if val, ok := dotEnv[name]; ok {
+				v.Set(prefix, val)
+			}
+		}
+		return
+	}
+
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if !f.IsExported() {
+			continue
+		}
+		key, _ := fieldKey(f, prefix)
+		bindEnvs(v, f.Type, key, dotEnv)
+	}
+}
@@ -0,0 +1,158 @@
+package config
+
+import (
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const schemaDraft = "http://json-schema.org/draft-07/schema#"
+
+var secretStringType = reflect.TypeOf(SecretString(""))
+
+// JSONSchema describes the config struct cfg as a JSON schema for editors,
+// using mapstructure keys and the default and validate tags.
+func JSONSchema(cfg interface{}) map[string]interface{} {
+	s := schemaFor(reflect.TypeOf(cfg), reflect.StructField{})
+	s["$schema"] = schemaDraft
+	return s
+}
+
+func schemaFor(t reflect.Type, f reflect.StructField) map[string]interface{} {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	s := make(map[string]interface{})
+	switch {
+	case t == durationType:
+		s["type"] = "string"
+		s["pattern"] = `^(\d+(\.\d+)?(ns|us|µs|ms|s|m|h))+$`
+	case t == reflect.TypeOf(time.Time{}):
+		s["type"] = "string"
+		s["format"] = "date-time"
+	case t == secretType:
+		s["type"] = "string"
+		s["writeOnly"] = true
+	case t.Kind() == reflect.Struct:
+		s["type"] = "object"
+		props := make(map[string]interface{})
+		var required []string
+		addProperties(t, props, &required)
+		s["properties"] = props
+		s["additionalProperties"] = false
+		if len(required) > 0 {
+			s["required"] = required
+		}
+	case t.Kind() == reflect.Slice || t.Kind() == reflect.Array:
+		s["type"] = "array"
+		s["items"] = schemaFor(t.Elem(), reflect.StructField{})
+	case t.Kind() == reflect.Map:
+		s["type"] = "object"
+		s["additionalProperties"] = schemaFor(t.Elem(), reflect.StructField{})
+	case t.Kind() == reflect.Bool:
+		s["type"] = "boolean"
+	case t.Kind() == reflect.String:
+		s["type"] = "string"
+		if t == secretStringType {
+			s["writeOnly"] = true
+		}
+	case t.Kind() >= reflect.Int && t.Kind() <= reflect.Uint64:
+		s["type"] = "integer"
+		if t.Kind() >= reflect.Uint {
+			s["minimum"] = 0
+		}
+	case t.Kind() == reflect.Float32 || t.Kind() == reflect.Float64:
+		s["type"] = "number"
+	}
+
+	applyTags(s, t, f)
+	return s
+}
+
+func addProperties(t reflect.Type, props map[string]interface{}, required *[]string) {
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if !f.IsExported() {
+			continue
+		}
+		key, squash := fieldKey(f, "")
+		if squash {
+			ft := f.Type
+			for ft.Kind() == reflect.Ptr {
+				ft = ft.Elem()
+			}
+			addProperties(ft, props, required)
+			continue
+		}
+
+		props[key] = schemaFor(f.Type, f)
+		for _, rule := range strings.Split(f.Tag.Get(validateTag), ",") {
+			if strings.TrimSpace(rule) == "required" {
+				*required = append(*required, key)
+			}
+		}
+	}
+}
+
+func applyTags(s map[string]interface{}, t reflect.Type, f reflect.StructField) {
+	if def, ok := f.Tag.Lookup(defaultTag); ok {
+		s["default"] = typedValue(t, def)
+	}
+
+	rules, ok := f.Tag.Lookup(validateTag)
+	if !ok {
+		return
+	}
+	for _, rule := range strings.Split(rules, ",") {
+		name, arg, _ := strings.Cut(strings.TrimSpace(rule), "=")
+		switch name {
+		case "oneof":
+			var enum []interface{}
+			for _, v := range strings.Fields(arg) {
+				enum = append(enum, typedValue(t, v))
+			}
+			s["enum"] = enum
+		case "min", "max":
+			if t != durationType {
+				s[boundKeyword(s["type"], name)] = typedValue(t, arg)
+			}
+		case "duration":
+			s["pattern"] = `^(\d+(\.\d+)?(ns|us|µs|ms|s|m|h))+$`
+		}
+	}
+}
+
+func boundKeyword(typ interface{}, rule string) string {
+	switch typ {
+	case "string":
+		return rule + "Length"
+	case "array":
+		return rule + "Items"
+	}
+	return rule + "imum"
+}
+
+// typedValue converts a tag value to the JSON type of t, durations stay strings.
+func typedValue(t reflect.Type, v string) interface{} {
+	if t == durationType {
+		return v
+	}
+	switch t.Kind() {
+	case reflect.Bool:
+		if b, err := strconv.ParseBool(v); err == nil {
+			return b
+		}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		if i, err := strconv.ParseInt(v, 10, 64); err == nil {
+			return i
+		}
+	case reflect.Float32, reflect.Float64:
+		if f, err := strconv.ParseFloat(v, 64); err == nil {
+			return f
+		}
+	}
+	return v
+}
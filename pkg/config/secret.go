@@ -0,0 +1,239 @@
+package config
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+
+	"example/pkg/logger"
+
+	"github.com/mitchellh/mapstructure"
+	"go.uber.org/zap/zapcore"
+)
+
+const (
+	secretMask = "*********"
+	// secretFilePrefix marks a secret value as a path to the file holding it,
+	// e.g. file:///var/run/secrets/db/password.
+	secretFilePrefix = "file://"
+)
+
+var (
+	secretType = reflect.TypeOf(Secret{})
+
+	secretKeyParts = []string{"password", "passwd", "secret", "token", "api_key", "apikey", "private_key", "credential"}
+)
+
+// SecretString is a secret read once at load time. It is masked by fmt, json,
+// text and zap encoders, only Value returns the secret.
+type SecretString string
+
+func (s SecretString) String() string               { return secretMask }
+func (s SecretString) Value() string                { return string(s) }
+func (s SecretString) Format(f fmt.State, _ rune)   { _, _ = f.Write([]byte(secretMask)) }
+func (s SecretString) MarshalJSON() ([]byte, error) { return []byte(`"` + secretMask + `"`), nil }
+func (s SecretString) MarshalText() ([]byte, error) { return []byte(secretMask), nil }
+func (s SecretString) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	enc.AddString("value", secretMask)
+	return nil
+}
+
+// Secret is a secret that follows rotation: when it is loaded from a file://
+// reference, Value re-reads the file after it changes, as Kubernetes does
+// with mounted secrets. It is masked like SecretString. The zero Secret is empty.
+type Secret struct {
+	s *secret
+}
+
+type secret struct {
+	m       sync.Mutex
+	path    string
+	value   []byte
+	modTime time.Time
+	size    int64
+	zeroed  bool
+}
+
+// NewSecret returns a Secret holding value, or the content of the file when
+// value is a file:// reference.
+func NewSecret(value string) (Secret, error) {
+	if value == "" {
+		return Secret{}, nil
+	}
+
+	path, ok := strings.CutPrefix(value, secretFilePrefix)
+	if !ok {
+		return Secret{s: &secret{value: []byte(value)}}, nil
+	}
+
+	s := &secret{path: path}
+	if err := s.reload(); err != nil {
+		return Secret{}, err
+	}
+	return Secret{s: s}, nil
+}
+
+// Value returns the current secret. A rotated file is re-read, if that fails
+// the previous value is kept.
+func (s Secret) Value() string {
+	return string(s.Bytes())
+}
+
+// Bytes returns a copy of the current secret, which the caller may zero.
+func (s Secret) Bytes() []byte {
+	if s.s == nil {
+		return nil
+	}
+
+	s.s.m.Lock()
+	defer s.s.m.Unlock()
+
+	if s.s.path != "" && !s.s.zeroed {
+		if err := s.s.reload(); err != nil {
+			logger.Errorf(context.Background(), "Config: reload secret %s: %v", s.s.path, err)
+		}
+	}
+	return append([]byte(nil), s.s.value...)
+}
+
+// Zero overwrites the secret in memory and stops following the file.
+func (s Secret) Zero() {
+	if s.s == nil {
+		return
+	}
+
+	s.s.m.Lock()
+	defer s.s.m.Unlock()
+
+	for i := range s.s.value {
+		s.s.value[i] = 0
+	}
+	s.s.value = nil
+	s.s.zeroed = true
+}
+
+func (s Secret) IsZero() bool {
+	return s.s == nil || len(s.Bytes()) == 0
+}
+
+func (s Secret) String() string               { return secretMask }
+func (s Secret) Format(f fmt.State, _ rune)   { _, _ = f.Write([]byte(secretMask)) }
+func (s Secret) MarshalJSON() ([]byte, error) { return []byte(`"` + secretMask + `"`), nil }
+func (s Secret) MarshalText() ([]byte, error) { return []byte(secretMask), nil }
+func (s Secret) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	enc.AddString("value", secretMask)
+	return nil
+}
+
+// reload reads the file if its size or modification time changed. The caller
+// holds s.m unless s is not shared yet.
+func (s *secret) reload() error {
+	fi, err := os.Stat(s.path)
+	if err != nil {
+		return err
+	}
+	if s.value != nil && fi.ModTime().Equal(s.modTime) && fi.Size() == s.size {
+		return nil
+	}
+
+	value, err := readSecretFile(s.path)
+	if err != nil {
+		return err
+	}
+	for i := range s.value {
+		s.value[i] = 0
+	}
+	s.value, s.modTime, s.size = value, fi.ModTime(), fi.Size()
+	return nil
+}
+
+func readSecretFile(path string) ([]byte, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	n := len(b)
+	for n > 0 && (b[n-1] == '\n' || b[n-1] == '\r') {
+		n--
+	}
+	return b[:n], nil
+}
+
+// secretHook resolves file:// references for SecretString and Secret fields.
+func secretHook(from, to reflect.Type, data interface{}) (interface{}, error) {
+	if from.Kind() != reflect.String {
+		return data, nil
+	}
+	value := reflect.ValueOf(data).String()
+
+	switch to {
+	case secretType:
+		return NewSecret(value)
+	case secretStringType:
+		path, ok := strings.CutPrefix(value, secretFilePrefix)
+		if !ok {
+			return data, nil
+		}
+		b, err := readSecretFile(path)
+		if err != nil {
+			return nil, err
+		}
+		return string(b), nil
+	}
+	return data, nil
+}
+
+func decodeHook() mapstructure.DecodeHookFunc {
+	// viper's default hooks are replaced by the option, keep them.
+	return mapstructure.ComposeDecodeHookFunc(
+		secretHook,
+		mapstructure.StringToTimeDurationHookFunc(),
+		mapstructure.StringToSliceHookFunc(","),
+	)
+}
+
+// checkSecrets reports keys that look like secrets, e.g. db.password or
+// jwt_secret, but are plain strings that fmt and loggers would print.
+func checkSecrets(t reflect.Type, prefix string, errs *ValidationError) {
+	for t.Kind() == reflect.Ptr || t.Kind() == reflect.Slice || t.Kind() == reflect.Array {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct || isLeaf(t) {
+		return
+	}
+
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if !f.IsExported() {
+			continue
+		}
+		key, _ := fieldKey(f, prefix)
+
+		ft := f.Type
+		for ft.Kind() == reflect.Ptr {
+			ft = ft.Elem()
+		}
+		if ft.Kind() == reflect.String && ft != secretStringType && isSecretKey(key) {
+			*errs = append(*errs, FieldError{
+				Key:     key,
+				Message: fmt.Sprintf("looks like a secret, use config.SecretString or config.Secret instead of %s", ft),
+			})
+			continue
+		}
+		checkSecrets(f.Type, key, errs)
+	}
+}
+
+func isSecretKey(key string) bool {
+	name := strings.ToLower(key[strings.LastIndex(key, ".")+1:])
+	for _, part := range secretKeyParts {
+		if strings.Contains(name, part) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,228 @@
+package config
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+const (
+	defaultTag  = "default"
+	validateTag = "validate"
+	keyTag      = "mapstructure"
+)
+
+var durationType = reflect.TypeOf(time.Duration(0))
+
+type FieldError struct {
+	Key     string
+	Message string
+}
+
+func (e FieldError) Error() string { return e.Key + ": " + e.Message }
+
+// ValidationError lists every config key that failed validation.
+type ValidationError []FieldError
+
+func (e ValidationError) Error() string {
+	msgs := make([]string, len(e))
+	for i := range e {
+		msgs[i] = e[i].Error()
+	}
+	return "invalid config: " + strings.Join(msgs, "; ")
+}
+
+// setDefaults registers `default:"..."` tags as viper defaults, so values set
+// in any source, even zero ones, take precedence over them.
+func setDefaults(v *viper.Viper, t reflect.Type, prefix string) {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return
+	}
+
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if !f.IsExported() {
+			continue
+		}
+		key, squash := fieldKey(f, prefix)
+		if squash {
+			setDefaults(v, f.Type, prefix)
+			continue
+		}
+
+		if def, ok := f.Tag.Lookup(defaultTag); ok {
+			v.SetDefault(key, def)
+			continue
+		}
+		setDefaults(v, f.Type, key)
+	}
+}
+
+// validate checks `validate:"..."` tags. Supported rules: required, min=N,
+// max=N, oneof=a b c and duration. Rules other than required are skipped for
+// zero values. min and max compare numbers and durations by value, strings
+// and slices by length. Secret-looking keys held in plain strings are errors.
+func validate(v reflect.Value, prefix string) error {
+	var errs ValidationError
+	validateValue(v, prefix, &errs)
+	checkSecrets(v.Type(), prefix, &errs)
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+func validateValue(v reflect.Value, prefix string, errs *ValidationError) {
+	for v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return
+		}
+		v = v.Elem()
+	}
+
+	switch v.Kind() {
+	case reflect.Struct:
+		t := v.Type()
+		for i := 0; i < t.NumField(); i++ {
+			f := t.Field(i)
+			if !f.IsExported() {
+				continue
+			}
+			key, squash := fieldKey(f, prefix)
+			if squash {
+				validateValue(v.Field(i), prefix, errs)
+				continue
+			}
+			if rules, ok := f.Tag.Lookup(validateTag); ok {
+				for _, msg := range checkRules(v.Field(i), rules) {
+					*errs = append(*errs, FieldError{Key: key, Message: msg})
+				}
+			}
+			validateValue(v.Field(i), key, errs)
+		}
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			validateValue(v.Index(i), fmt.Sprintf("%s[%d]", prefix, i), errs)
+		}
+	}
+}
+
+func checkRules(v reflect.Value, rules string) []string {
+	var msgs []string
+
+	isZero := v.IsZero()
+	for v.Kind() == reflect.Ptr && !v.IsNil() {
+		v = v.Elem()
+	}
+
+	for _, rule := range strings.Split(rules, ",") {
+		name, arg, _ := strings.Cut(strings.TrimSpace(rule), "=")
+		if name == "required" {
+			if isZero {
+				msgs = append(msgs, "is required")
+			}
+			continue
+		}
+		if isZero {
+			continue
+		}
+
+		var msg string
+		switch name {
+		case "min":
+			msg = checkBound(v, arg, true)
+		case "max":
+			msg = checkBound(v, arg, false)
+		case "oneof":
+			msg = checkOneOf(v, arg)
+		case "duration":
+			if _, err := time.ParseDuration(v.String()); v.Kind() != reflect.String || err != nil {
+				msg = fmt.Sprintf("must be a duration like 1s or 1m30s, got %q", fmt.Sprint(v.Interface()))
+			}
+		default:
+			msg = fmt.Sprintf("unknown validation rule %q", name)
+		}
+		if msg != "" {
+			msgs = append(msgs, msg)
+		}
+	}
+	return msgs
+}
+
+func checkBound(v reflect.Value, arg string, isMin bool) string {
+	word, cmp := "most", func(a, b float64) bool { return a <= b }
+	if isMin {
+		word, cmp = "least", func(a, b float64) bool { return a >= b }
+	}
+
+	var actual, bound float64
+	var err error
+	switch {
+	case v.Type() == durationType:
+		var d time.Duration
+		d, err = time.ParseDuration(arg)
+		actual, bound = float64(v.Int()), float64(d)
+	case v.Kind() == reflect.String, v.Kind() == reflect.Slice, v.Kind() == reflect.Map:
+		actual = float64(v.Len())
+		bound, err = strconv.ParseFloat(arg, 64)
+		word += " " + arg + " long"
+		arg = ""
+	case v.CanInt():
+		actual = float64(v.Int())
+		bound, err = strconv.ParseFloat(arg, 64)
+	case v.CanUint():
+		actual = float64(v.Uint())
+		bound, err = strconv.ParseFloat(arg, 64)
+	case v.CanFloat():
+		actual = v.Float()
+		bound, err = strconv.ParseFloat(arg, 64)
+	default:
+		return fmt.Sprintf("min/max is not supported for %s", v.Kind())
+	}
+	if err != nil {
+		return fmt.Sprintf("bad bound %q: %v", arg, err)
+	}
+
+	if cmp(actual, bound) {
+		return ""
+	}
+	if arg == "" {
+		return fmt.Sprintf("must be at %s", word)
+	}
+	return fmt.Sprintf("must be at %s %s, got %v", word, arg, v.Interface())
+}
+
+func checkOneOf(v reflect.Value, arg string) string {
+	actual := fmt.Sprint(v.Interface())
+	if v.Kind() == reflect.String {
+		actual = v.String()
+	}
+	allowed := strings.Fields(arg)
+	for _, a := range allowed {
+		if a == actual {
+			return ""
+		}
+	}
+	return fmt.Sprintf("must be one of [%s], got %q", strings.Join(allowed, " "), actual)
+}
+
+func fieldKey(f reflect.StructField, prefix string) (key string, squash bool) {
+	name, opts, _ := strings.Cut(f.Tag.Get(keyTag), ",")
+	if strings.Contains(opts, "squash") {
+		return prefix, true
+	}
+	if name == "" {
+		name = strings.ToLower(f.Name)
+	}
+	if prefix == "" {
+		return name, false
+	}
+	return prefix + "." + name, false
+}
@@ -0,0 +1,124 @@
+package config
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+type validateConfig struct {
+	Name    string         `mapstructure:"name" validate:"required"`
+	Mode    string         `mapstructure:"mode" validate:"oneof=fast safe"`
+	Workers *int           `mapstructure:"workers" validate:"min=1,max=8"`
+	Timeout time.Duration  `mapstructure:"timeout" validate:"min=1s,max=1m"`
+	Delay   *time.Duration `mapstructure:"delay" validate:"max=10s"`
+	Tags    []string       `mapstructure:"tags" validate:"max=2"`
+	Nodes   []validateNode `mapstructure:"nodes"`
+}
+
+type validateNode struct {
+	Host string `mapstructure:"host" validate:"required"`
+}
+
+func validConfig() validateConfig {
+	return validateConfig{Name: "app", Mode: "fast", Timeout: time.Second}
+}
+
+func ptr[T any](v T) *T { return &v }
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(*validateConfig)
+		want   ValidationError
+	}{
+		{
+			name:   "valid",
+			modify: func(*validateConfig) {},
+		},
+		{
+			name:   "required",
+			modify: func(c *validateConfig) { c.Name = "" },
+			want:   ValidationError{{Key: "name", Message: "is required"}},
+		},
+		{
+			name:   "required in slice",
+			modify: func(c *validateConfig) { c.Nodes = []validateNode{{Host: "a"}, {}} },
+			want:   ValidationError{{Key: "nodes[1].host", Message: "is required"}},
+		},
+		{
+			name:   "oneof",
+			modify: func(c *validateConfig) { c.Mode = "slow" },
+			want:   ValidationError{{Key: "mode", Message: `must be one of [fast safe], got "slow"`}},
+		},
+		{
+			name:   "oneof skips zero",
+			modify: func(c *validateConfig) { c.Mode = "" },
+		},
+		{
+			name:   "min on pointer",
+			modify: func(c *validateConfig) { c.Workers = ptr(0) },
+			want:   ValidationError{{Key: "workers", Message: "must be at least 1, got 0"}},
+		},
+		{
+			name:   "max on pointer",
+			modify: func(c *validateConfig) { c.Workers = ptr(9) },
+			want:   ValidationError{{Key: "workers", Message: "must be at most 8, got 9"}},
+		},
+		{
+			name:   "nil pointer",
+			modify: func(c *validateConfig) { c.Workers = nil },
+		},
+		{
+			name:   "min duration",
+			modify: func(c *validateConfig) { c.Timeout = time.Millisecond },
+			want:   ValidationError{{Key: "timeout", Message: "must be at least 1s, got 1ms"}},
+		},
+		{
+			name:   "max duration",
+			modify: func(c *validateConfig) { c.Timeout = 2 * time.Minute },
+			want:   ValidationError{{Key: "timeout", Message: "must be at most 1m, got 2m0s"}},
+		},
+		{
+			name:   "max duration on pointer",
+			modify: func(c *validateConfig) { c.Delay = ptr(time.Minute) },
+			want:   ValidationError{{Key: "delay", Message: "must be at most 10s, got 1m0s"}},
+		},
+		{
+			name:   "max length",
+			modify: func(c *validateConfig) { c.Tags = []string{"a", "b", "c"} },
+			want:   ValidationError{{Key: "tags", Message: "must be at most 2 long"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := validConfig()
+			tt.modify(&cfg)
+
+			err := validate(reflect.ValueOf(&cfg), "")
+			if tt.want == nil {
+				if err != nil {
+					t.Fatalf("validate = %v, want nil", err)
+				}
+				return
+			}
+			if !reflect.DeepEqual(err, tt.want) {
+				t.Fatalf("validate = %#v, want %#v", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidationErrorMessage(t *testing.T) {
+	cfg := validConfig()
+	cfg.Name = ""
+	cfg.Mode = "slow"
+	cfg.Workers = ptr(9)
+
+	err := validate(reflect.ValueOf(&cfg), "app")
+	want := `invalid config: app.name: is required; app.mode: must be one of [fast safe], got "slow"; app.workers: must be at most 8, got 9`
+	if err == nil || err.Error() != want {
+		t.Fatalf("validate = %v, want %s", err, want)
+	}
+}
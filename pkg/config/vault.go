@@ -0,0 +1,108 @@
+package config
+
+import (
+	"context"
+	"strings"
+
+	"example/pkg/logger"
+
+	vault "github.com/hashicorp/vault/api"
+	"github.com/pkg/errors"
+	"github.com/spf13/viper"
+)
+
+const vaultDefaultMount = "secret"
+
+// VaultOptions points at a Vault KV v2 secret. Its keys are config key paths,
+// either nested objects or dotted names like "db.db.password". Empty fields
+// fall back to the VAULT_* environment variables.
+type VaultOptions struct {
+	Address string
+	Token   string
+	// Mount defaults to "secret".
+	Mount string
+	// Path defaults to <AppName>/<EnvName>.
+	Path string
+}
+
+func fromVault(v *viper.Viper, opts *Options, names EnvNames, o *origins) (bool, error) {
+	if opts.Vault == nil {
+		return false, nil
+	}
+
+	c := vault.DefaultConfig()
+	if opts.Vault.Address != "" {
+		c.Address = opts.Vault.Address
+	}
+
+	client, err := vault.NewClient(c)
+	if err != nil {
+		return false, errors.Wrap(err, "failed to create vault client")
+	}
+	if opts.Vault.Token != "" {
+		client.SetToken(opts.Vault.Token)
+	}
+
+	mount := opts.Vault.Mount
+	if mount == "" {
+		mount = vaultDefaultMount
+	}
+	path := opts.Vault.Path
+	if path == "" {
+		path = names.AppName + "/" + names.EnvName
+	}
+
+	secret, err := client.KVv2(mount).Get(context.Background(), path)
+	if err != nil {
+		return false, errors.Wrapf(err, "failed to read vault secret %s/%s", mount, path)
+	}
+
+	values := expandKeys(secret.Data)
+	if err := v.MergeConfigMap(values); err != nil {
+		return false, errors.Wrap(err, "failed to merge vault config")
+	}
+	o.set(flattenKeys(values, ""), "vault:"+mount+"/"+path)
+	logger.Debugf(context.Background(), "Config: load from vault (%s/%s)", mount, path)
+
+	return true, nil
+}
+
+// expandKeys turns dotted keys into nested maps, so {"db.db.password": "x"}
+// overrides the same key as {"db": {"db": {"password": "x"}}}.
+func expandKeys(data map[string]interface{}) map[string]interface{} {
+	res := make(map[string]interface{}, len(data))
+	for k, v := range data {
+		parts := strings.Split(k, ".")
+		m := res
+		for _, p := range parts[:len(parts)-1] {
+			next, ok := m[p].(map[string]interface{})
+			if !ok {
+				next = make(map[string]interface{})
+				m[p] = next
+			}
+			m = next
+		}
+		last := parts[len(parts)-1]
+		if nested, ok := v.(map[string]interface{}); ok {
+			v = expandKeys(nested)
+			if existing, ok := m[last].(map[string]interface{}); ok {
+				mergeMaps(existing, v.(map[string]interface{}))
+				continue
+			}
+		}
+		m[last] = v
+	}
+	return res
+}
+
+func mergeMaps(dst, src map[string]interface{}) {
+	for k, v := range src {
+		if sm, ok := v.(map[string]interface{}); ok {
+			if dm, ok := dst[k].(map[string]interface{}); ok {
+				mergeMaps(dm, sm)
+				continue
+			}
+		}
+		dst[k] = v
+	}
+}
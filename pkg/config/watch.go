@@ -0,0 +1,202 @@
+package config
+
+import (
+	"context"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"example/pkg/goruntime"
+	"example/pkg/logger"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/pkg/errors"
+)
+
+const reloadDebounce = 250 * time.Millisecond
+
+// Watcher holds the current config and reloads it when the config file or
+// one of its overlays changes or, for remote sources, every Options.ReloadInterval.
+type Watcher[T any] struct {
+	opts     Options
+	loader   *Loader
+	newCfg   func() *T
+	validate func(*T) error
+
+	current atomic.Pointer[T]
+
+	m    sync.Mutex
+	subs []func(old, new *T)
+
+	fsw  *fsnotify.Watcher
+	stop chan struct{}
+	wg   sync.WaitGroup
+
+	closeOnce sync.Once
+	closeErr  error
+}
+
+// NewWatcher loads the config and starts watching its sources. newCfg returns
+// a value filled with defaults, validate may be nil. A reloaded config that
+// fails to load or validate is logged and dropped, the previous one stays.
+func NewWatcher[T any](opts Options, newCfg func() *T, validate func(*T) error) (*Watcher[T], error) {
+	w := &Watcher[T]{
+		opts:     opts,
+		loader:   NewLoader(opts),
+		newCfg:   newCfg,
+		validate: validate,
+		stop:     make(chan struct{}),
+	}
+
+	cfg, err := w.load()
+	if err != nil {
+		return nil, err
+	}
+	w.current.Store(cfg)
+
+	w.fsw, err = fsnotify.NewWatcher()
+	if err != nil {
+		return nil, errors.Wrap(err, "create file watcher")
+	}
+
+	opts.Fill()
+	if err := w.fsw.Add(opts.Dir); err != nil {
+		if opts.Consul == nil && opts.Vault == nil {
+			_ = w.fsw.Close()
+			return nil, errors.Wrapf(err, "watch %s", opts.Dir)
+		}
+		logger.Warnf(context.Background(), "Config: not watching %s: %v", opts.Dir, err)
+	}
+
+	names, err := w.loader.EnvNames()
+	if err != nil {
+		_ = w.fsw.Close()
+		return nil, err
+	}
+	files := make(map[string]struct{})
+	for _, f := range configFiles(&opts, names) {
+		files[filepath.Clean(f)] = struct{}{}
+	}
+	if opts.ReplaceFromEnvVars {
+		for _, f := range opts.DotEnv {
+			files[filepath.Clean(f)] = struct{}{}
+			if dir := filepath.Dir(f); filepath.Clean(dir) != filepath.Clean(opts.Dir) {
+				if err := w.fsw.Add(dir); err != nil {
+					logger.Warnf(context.Background(), "Config: not watching %s: %v", dir, err)
+				}
+			}
+		}
+	}
+
+	w.wg.Add(1)
+	goruntime.Go(context.Background(), "config-watcher", func(context.Context) { w.run(files) })
+
+	return w, nil
+}
+
+// Get returns the current config. The value must not be modified.
+func (w *Watcher[T]) Get() *T {
+	return w.current.Load()
+}
+
+// Subscribe registers f to be called with the previous and the new config
+// after every successful reload.
+func (w *Watcher[T]) Subscribe(f func(old, new *T)) {
+	w.m.Lock()
+	w.subs = append(w.subs, f)
+	w.m.Unlock()
+}
+
+// Reload re-reads all sources and, if the result is valid, swaps it in and
+// notifies subscribers. Subscribers are called without the lock held, so they
+// may call Get, Subscribe or Reload.
+func (w *Watcher[T]) Reload() error {
+	w.m.Lock()
+	cfg, err := w.load()
+	if err != nil {
+		w.m.Unlock()
+		return err
+	}
+	old := w.current.Swap(cfg)
+	subs := make([]func(old, new *T), len(w.subs))
+	copy(subs, w.subs)
+	w.m.Unlock()
+
+	for _, f := range subs {
+		f(old, cfg)
+	}
+	return nil
+}
+
+// Close stops watching. Later calls return the result of the first one.
+func (w *Watcher[T]) Close() error {
+	w.closeOnce.Do(func() {
+		close(w.stop)
+		w.closeErr = w.fsw.Close()
+		w.wg.Wait()
+	})
+	return w.closeErr
+}
+
+func (w *Watcher[T]) load() (*T, error) {
+	cfg := w.newCfg()
+
+	if err := w.loader.Load(cfg); err != nil {
+		return nil, err
+	}
+
+	if w.validate != nil {
+		if err := w.validate(cfg); err != nil {
+			return nil, errors.Wrap(err, "validate config")
+		}
+	}
+	return cfg, nil
+}
+
+func (w *Watcher[T]) run(files map[string]struct{}) {
+	defer w.wg.Done()
+
+	var poll <-chan time.Time
+	if w.opts.ReloadInterval > 0 && (w.opts.Consul != nil || w.opts.Vault != nil) {
+		t := time.NewTicker(w.opts.ReloadInterval)
+		defer t.Stop()
+		poll = t.C
+	}
+
+	debounce := time.NewTimer(reloadDebounce)
+	debounce.Stop()
+
+	for {
+		select {
+		case <-w.stop:
+			debounce.Stop()
+			return
+		case ev, ok := <-w.fsw.Events:
+			if !ok {
+				return
+			}
+			// Kubernetes mounts swap the ..data symlink instead of writing the file.
+			if _, ok := files[filepath.Clean(ev.Name)]; ok || filepath.Base(ev.Name) == "..data" {
+				debounce.Reset(reloadDebounce)
+			}
+		case err, ok := <-w.fsw.Errors:
+			if !ok {
+				return
+			}
+			logger.Errorf(context.Background(), "Config: watch error: %v", err)
+		case <-debounce.C:
+			w.reload("file changed")
+		case <-poll:
+			w.reload("poll")
+		}
+	}
+}
+
+func (w *Watcher[T]) reload(reason string) {
+	if err := w.Reload(); err != nil {
+		logger.Errorf(context.Background(), "Config: reload (%s) failed, keeping the previous config: %v", reason, err)
+		return
+	}
+	logger.Warnf(context.Background(), "Config: reloaded (%s)", reason)
+}
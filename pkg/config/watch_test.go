@@ -0,0 +1,63 @@
+package config
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func newTestWatcher(t *testing.T, dir string) *Watcher[dotEnvConfig] {
+	t.Helper()
+	w, err := NewWatcher(Options{Dir: dir}, func() *dotEnvConfig { return &dotEnvConfig{} }, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = w.Close() })
+	return w
+}
+
+func TestWatcherSubscriberCanUseWatcher(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "values.json"), `{"db": {"host": "first"}}`)
+	w := newTestWatcher(t, dir)
+
+	got := make(chan string, 1)
+	w.Subscribe(func(old, new *dotEnvConfig) {
+		// Subscribe used to deadlock on the lock held by Reload.
+		w.Subscribe(func(old, new *dotEnvConfig) {})
+		// The file watcher may reload too, only the first result counts.
+		select {
+		case got <- w.Get().DB.Host:
+		default:
+		}
+	})
+
+	writeFile(t, filepath.Join(dir, "values.json"), `{"db": {"host": "second"}}`)
+	done := make(chan error, 1)
+	go func() { done <- w.Reload() }()
+
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatal(err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Reload deadlocked on a subscriber using the watcher")
+	}
+	if host := <-got; host != "second" {
+		t.Fatalf("Get in subscriber = %q, want second", host)
+	}
+}
+
+func TestWatcherCloseTwice(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "values.json"), `{}`)
+	w := newTestWatcher(t, dir)
+
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("second Close: %v", err)
+	}
+}
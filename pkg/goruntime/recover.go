@@ -2,21 +2,67 @@ package goruntime
 
 import (
 	"context"
+	"crypto/rand"
+	"encoding/hex"
 	"example/pkg/logger"
+	"example/pkg/requestid"
 	"fmt"
 	"runtime/debug"
 	"strings"
+
+	"github.com/prometheus/client_golang/prometheus"
 )
 
+var panicsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+	Namespace: "goruntime",
+	Name:      "panics_total",
+	Help:      "Number of recovered panics by goroutine or handler name.",
+}, []string{"name"})
+
+func init() {
+	prometheus.MustRegister(panicsTotal)
+}
+
+// RecoverPanic must be deferred directly, it recovers a panic and reports it
+// with HandlePanic.
 func RecoverPanic(ctx context.Context, name string) {
 	if p := recover(); p != nil {
-		
+		HandlePanic(ctx, name, p)
+	}
+}
+
+// HandlePanic logs p with the stack, counts it and returns the debug ID the
+// log entry carries, so it can be shown to the client.
+func HandlePanic(ctx context.Context, name string, p interface{}) string {
+	if p == nil {
+		return ""
 	}
+
+	debugID := newDebugID(ctx)
+	panicsTotal.WithLabelValues(name).Inc()
+
+	stack := strings.Split(string(debug.Stack()), "\n")
+	logger.ErrorKV(ctx, fmt.Sprintf("%s %v", name, p), "stack_trace", stack, "debug_id", debugID)
+	return debugID
 }
 
-func HandlePanic(ctx context.Context, name string, p interface{}) {
-	if p != nil {
-		stack := strings.Split(string(debug.Stack()),"\n")
-		logger.ErrorKV(ctx, fmt.Sprintf("%s %v", name, p), "stack_trace", stack)
+// Go runs fn in a goroutine, a panic in fn is reported like in RecoverPanic
+// instead of crashing the process.
+func Go(ctx context.Context, name string, fn func(ctx context.Context)) {
+	go func() {
+		defer RecoverPanic(ctx, name)
+		fn(ctx)
+	}()
+}
+
+// newDebugID returns the request ID of ctx, if any, so the client and the
+// logs of the whole request share it.
+func newDebugID(ctx context.Context) string {
+	if id := requestid.FromContext(ctx); id != "" {
+		return id
 	}
+
+	b := make([]byte, 8)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
 }
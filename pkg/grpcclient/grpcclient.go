@@ -0,0 +1,48 @@
+// Package grpcclient builds connections to other services that carry the
+// request ID and the trace context of the caller.
+package grpcclient
+
+import (
+	"context"
+
+	"example/pkg/requestid"
+	"example/pkg/tracing"
+
+	"github.com/pkg/errors"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc"
+)
+
+// New returns a client connection to target. The interceptors forwarding the
+// request ID and the trace context run before the ones of opts.
+func New(target string, opts ...grpc.DialOption) (*grpc.ClientConn, error) {
+	opts = append([]grpc.DialOption{
+		grpc.WithChainUnaryInterceptor(requestid.UnaryClientInterceptor, tracingUnary),
+		grpc.WithChainStreamInterceptor(requestid.StreamClientInterceptor, tracingStream),
+	}, opts...)
+
+	conn, err := grpc.NewClient(target, opts...)
+	if err != nil {
+		return nil, errors.Wrapf(err, "grpc client %s", target)
+	}
+	return conn, nil
+}
+
+// tracingUnary starts a client span for the call and propagates it to the
+// server.
+func tracingUnary(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+	ctx, span := tracing.Start(ctx, method,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(semconv.RPCSystemGRPC, semconv.ServerAddress(cc.Target())),
+	)
+	err := invoker(tracing.InjectGRPC(ctx), method, req, reply, cc, opts...)
+	tracing.EndGRPC(span, err)
+	return err
+}
+
+// tracingStream propagates the span of the caller, a stream has no end the
+// interceptor could wait for.
+func tracingStream(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+	return streamer(tracing.InjectGRPC(ctx), desc, cc, method, opts...)
+}
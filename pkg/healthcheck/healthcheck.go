@@ -0,0 +1,208 @@
+package healthcheck
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+const defaultTimeout = time.Second
+
+var gRegistry = New()
+
+// Default returns the registry behind the package functions.
+func Default() *Registry {
+	return gRegistry
+}
+
+func Register(name string, c Checker, opts ...Option) {
+	gRegistry.Register(name, c, opts...)
+}
+
+func Shutdown() {
+	gRegistry.Shutdown()
+}
+
+func Live(ctx context.Context) Report {
+	return gRegistry.Live(ctx)
+}
+
+func Ready(ctx context.Context) Report {
+	return gRegistry.Ready(ctx)
+}
+
+type Checker interface {
+	Check(ctx context.Context) error
+}
+
+type CheckerFunc func(ctx context.Context) error
+
+func (f CheckerFunc) Check(ctx context.Context) error { return f(ctx) }
+
+type Status string
+
+const (
+	StatusOK           Status = "ok"
+	StatusDegraded     Status = "degraded"
+	StatusFail         Status = "fail"
+	StatusShuttingDown Status = "shutting_down"
+)
+
+type Report struct {
+	Status Status            `json:"status"`
+	Checks map[string]Result `json:"checks,omitempty"`
+}
+
+type Result struct {
+	Status   Status `json:"status"`
+	Error    string `json:"error,omitempty"`
+	Critical bool   `json:"critical"`
+	Duration string `json:"duration"`
+}
+
+type check struct {
+	name     string
+	checker  Checker
+	timeout  time.Duration
+	critical bool
+	liveness bool
+}
+
+type Option func(*check)
+
+// WithTimeout bounds a single run of the check, one second by default.
+func WithTimeout(d time.Duration) Option {
+	return func(c *check) {
+		c.timeout = d
+	}
+}
+
+// NonCritical reports failures of the check without failing the probe.
+func NonCritical() Option {
+	return func(c *check) {
+		c.critical = false
+	}
+}
+
+// WithLiveness adds the check to the liveness probe as well as to readiness.
+func WithLiveness() Option {
+	return func(c *check) {
+		c.liveness = true
+	}
+}
+
+type Registry struct {
+	m            sync.RWMutex
+	checks       []*check
+	shuttingDown atomic.Bool
+}
+
+func New() *Registry {
+	return &Registry{}
+}
+
+func (r *Registry) Register(name string, c Checker, opts ...Option) {
+	ch := &check{
+		name:     name,
+		checker:  c,
+		timeout:  defaultTimeout,
+		critical: true,
+	}
+	for i := range opts {
+		opts[i](ch)
+	}
+
+	r.m.Lock()
+	r.checks = append(r.checks, ch)
+	r.m.Unlock()
+}
+
+// Shutdown makes readiness fail from now on, so balancers stop sending traffic
+// while the servers are draining.
+func (r *Registry) Shutdown() {
+	r.shuttingDown.Store(true)
+}
+
+func (r *Registry) Live(ctx context.Context) Report {
+	return r.run(ctx, func(c *check) bool { return c.liveness })
+}
+
+func (r *Registry) Ready(ctx context.Context) Report {
+	if r.shuttingDown.Load() {
+		return Report{Status: StatusShuttingDown}
+	}
+	return r.run(ctx, func(*check) bool { return true })
+}
+
+func (r *Registry) run(ctx context.Context, filter func(*check) bool) Report {
+	r.m.RLock()
+	checks := make([]*check, 0, len(r.checks))
+	for i := range r.checks {
+		if filter(r.checks[i]) {
+			checks = append(checks, r.checks[i])
+		}
+	}
+	r.m.RUnlock()
+
+	rep := Report{Status: StatusOK}
+	if len(checks) == 0 {
+		return rep
+	}
+
+	results := make([]Result, len(checks))
+	var wg sync.WaitGroup
+	for i := range checks {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			results[i] = checks[i].run(ctx)
+		}(i)
+	}
+	wg.Wait()
+
+	rep.Checks = make(map[string]Result, len(checks))
+	for i := range checks {
+		rep.Checks[checks[i].name] = results[i]
+		switch {
+		case results[i].Status == StatusOK:
+		case checks[i].critical:
+			rep.Status = StatusFail
+		case rep.Status == StatusOK:
+			rep.Status = StatusDegraded
+		}
+	}
+
+	return rep
+}
+
+func (c *check) run(ctx context.Context) Result {
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	started := time.Now()
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- c.checker.Check(ctx)
+	}()
+
+	var err error
+	select {
+	case err = <-errCh:
+	case <-ctx.Done():
+		err = errors.Wrap(ctx.Err(), "check timed out")
+	}
+
+	res := Result{
+		Status:   StatusOK,
+		Critical: c.critical,
+		Duration: time.Since(started).String(),
+	}
+	if err != nil {
+		res.Status = StatusFail
+		res.Error = err.Error()
+	}
+	return res
+}
@@ -0,0 +1,129 @@
+package healthcheck
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+func ok(context.Context) error { return nil }
+
+func fail(context.Context) error { return errors.New("broken") }
+
+// hang returns only after the check timed out.
+func hang(ctx context.Context) error {
+	<-ctx.Done()
+	time.Sleep(10 * time.Millisecond)
+	return nil
+}
+
+type testCheck struct {
+	name string
+	fn   CheckerFunc
+	opts []Option
+}
+
+func TestReady(t *testing.T) {
+	tests := []struct {
+		name   string
+		checks []testCheck
+		want   Status
+		failed map[string]string
+	}{
+		{
+			name: "no checks",
+			want: StatusOK,
+		},
+		{
+			name:   "all ok",
+			checks: []testCheck{{name: "db", fn: ok}, {name: "cache", fn: ok, opts: []Option{NonCritical()}}},
+			want:   StatusOK,
+		},
+		{
+			name:   "critical failure",
+			checks: []testCheck{{name: "db", fn: fail}, {name: "cache", fn: ok}},
+			want:   StatusFail,
+			failed: map[string]string{"db": "broken"},
+		},
+		{
+			name:   "non-critical failure",
+			checks: []testCheck{{name: "db", fn: ok}, {name: "cache", fn: fail, opts: []Option{NonCritical()}}},
+			want:   StatusDegraded,
+			failed: map[string]string{"cache": "broken"},
+		},
+		{
+			name: "critical wins over non-critical",
+			checks: []testCheck{
+				{name: "cache", fn: fail, opts: []Option{NonCritical()}},
+				{name: "db", fn: fail},
+			},
+			want:   StatusFail,
+			failed: map[string]string{"cache": "broken", "db": "broken"},
+		},
+		{
+			name:   "timeout",
+			checks: []testCheck{{name: "db", fn: hang, opts: []Option{WithTimeout(20 * time.Millisecond)}}},
+			want:   StatusFail,
+			failed: map[string]string{"db": "check timed out"},
+		},
+		{
+			name:   "non-critical timeout",
+			checks: []testCheck{{name: "db", fn: hang, opts: []Option{WithTimeout(20 * time.Millisecond), NonCritical()}}},
+			want:   StatusDegraded,
+			failed: map[string]string{"db": "check timed out"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := New()
+			for _, c := range tt.checks {
+				r.Register(c.name, c.fn, c.opts...)
+			}
+
+			rep := r.Ready(context.Background())
+			if rep.Status != tt.want {
+				t.Fatalf("status = %s, want %s: %+v", rep.Status, tt.want, rep.Checks)
+			}
+			if len(rep.Checks) != len(tt.checks) {
+				t.Fatalf("checks = %+v, want %d", rep.Checks, len(tt.checks))
+			}
+			for name, res := range rep.Checks {
+				want, failed := tt.failed[name]
+				switch {
+				case !failed && res.Status != StatusOK:
+					t.Errorf("%s = %+v, want ok", name, res)
+				case failed && (res.Status != StatusFail || !strings.Contains(res.Error, want)):
+					t.Errorf("%s = %+v, want a failure with %q", name, res, want)
+				}
+			}
+		})
+	}
+}
+
+func TestLiveRunsLivenessChecksOnly(t *testing.T) {
+	r := New()
+	r.Register("db", CheckerFunc(fail))
+	r.Register("process", CheckerFunc(ok), WithLiveness())
+
+	rep := r.Live(context.Background())
+	if rep.Status != StatusOK || len(rep.Checks) != 1 || rep.Checks["process"].Status != StatusOK {
+		t.Fatalf("Live = %+v", rep)
+	}
+}
+
+func TestShutdownFailsReadinessOnly(t *testing.T) {
+	r := New()
+	r.Register("process", CheckerFunc(ok), WithLiveness())
+	r.Shutdown()
+
+	if rep := r.Ready(context.Background()); rep.Status != StatusShuttingDown {
+		t.Fatalf("Ready after Shutdown = %+v", rep)
+	}
+	if rep := r.Live(context.Background()); rep.Status != StatusOK {
+		t.Fatalf("Live after Shutdown = %+v", rep)
+	}
+}
@@ -0,0 +1,77 @@
+package healthcheck
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+
+	"github.com/pkg/errors"
+)
+
+func LiveHandler() http.Handler {
+	return gRegistry.LiveHandler()
+}
+
+func ReadyHandler() http.Handler {
+	return gRegistry.ReadyHandler()
+}
+
+func (r *Registry) LiveHandler() http.Handler {
+	return reportHandler(r.Live)
+}
+
+func (r *Registry) ReadyHandler() http.Handler {
+	return reportHandler(r.Ready)
+}
+
+func reportHandler(probe func(ctx context.Context) Report) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		rep := probe(r.Context())
+
+		code := http.StatusOK
+		if rep.Status == StatusFail || rep.Status == StatusShuttingDown {
+			code = http.StatusServiceUnavailable
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Cache-Control", "no-store")
+		w.WriteHeader(code)
+		_ = json.NewEncoder(w).Encode(rep)
+	})
+}
+
+// HTTPGetCheck reports an error unless GET url answers with a 2xx status.
+func HTTPGetCheck(client *http.Client, url string) Checker {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	return CheckerFunc(func(ctx context.Context) error {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+		if err != nil {
+			return err
+		}
+		resp, err := client.Do(req)
+		if err != nil {
+			return err
+		}
+		_ = resp.Body.Close()
+		if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+			return fmt.Errorf("unexpected status code %d", resp.StatusCode)
+		}
+		return nil
+	})
+}
+
+// TCPDialCheck reports an error if a TCP connection to addr can't be opened.
+func TCPDialCheck(addr string) Checker {
+	return CheckerFunc(func(ctx context.Context) error {
+		var d net.Dialer
+		conn, err := d.DialContext(ctx, "tcp", addr)
+		if err != nil {
+			return errors.Wrapf(err, "dial %s", addr)
+		}
+		return conn.Close()
+	})
+}
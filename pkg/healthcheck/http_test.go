@@ -0,0 +1,140 @@
+package healthcheck
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestHandlers(t *testing.T) {
+	tests := []struct {
+		name     string
+		register func(r *Registry)
+		shutdown bool
+		handler  func(r *Registry) http.Handler
+		code     int
+		status   Status
+	}{
+		{
+			name:     "ready",
+			register: func(r *Registry) { r.Register("db", CheckerFunc(ok)) },
+			handler:  (*Registry).ReadyHandler,
+			code:     http.StatusOK,
+			status:   StatusOK,
+		},
+		{
+			name:     "ready degraded",
+			register: func(r *Registry) { r.Register("cache", CheckerFunc(fail), NonCritical()) },
+			handler:  (*Registry).ReadyHandler,
+			code:     http.StatusOK,
+			status:   StatusDegraded,
+		},
+		{
+			name:     "ready failed",
+			register: func(r *Registry) { r.Register("db", CheckerFunc(fail)) },
+			handler:  (*Registry).ReadyHandler,
+			code:     http.StatusServiceUnavailable,
+			status:   StatusFail,
+		},
+		{
+			name:     "ready after shutdown",
+			register: func(r *Registry) { r.Register("db", CheckerFunc(ok)) },
+			shutdown: true,
+			handler:  (*Registry).ReadyHandler,
+			code:     http.StatusServiceUnavailable,
+			status:   StatusShuttingDown,
+		},
+		{
+			name:     "live failed",
+			register: func(r *Registry) { r.Register("process", CheckerFunc(fail), WithLiveness()) },
+			handler:  (*Registry).LiveHandler,
+			code:     http.StatusServiceUnavailable,
+			status:   StatusFail,
+		},
+		{
+			name:     "live after shutdown",
+			register: func(r *Registry) { r.Register("process", CheckerFunc(ok), WithLiveness()) },
+			shutdown: true,
+			handler:  (*Registry).LiveHandler,
+			code:     http.StatusOK,
+			status:   StatusOK,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := New()
+			tt.register(r)
+			if tt.shutdown {
+				r.Shutdown()
+			}
+
+			w := httptest.NewRecorder()
+			tt.handler(r).ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+
+			var rep Report
+			if err := json.Unmarshal(w.Body.Bytes(), &rep); err != nil {
+				t.Fatalf("body %q: %v", w.Body, err)
+			}
+			if w.Code != tt.code || rep.Status != tt.status {
+				t.Fatalf("= %d %s, want %d %s", w.Code, rep.Status, tt.code, tt.status)
+			}
+			if cc := w.Header().Get("Cache-Control"); cc != "no-store" {
+				t.Errorf("Cache-Control = %q", cc)
+			}
+		})
+	}
+}
+
+func TestHTTPGetCheck(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/ok" {
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	}))
+	t.Cleanup(srv.Close)
+
+	tests := []struct {
+		name    string
+		url     string
+		wantErr bool
+	}{
+		{name: "2xx", url: srv.URL + "/ok"},
+		{name: "5xx", url: srv.URL + "/broken", wantErr: true},
+		{name: "invalid url", url: "://", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := HTTPGetCheck(srv.Client(), tt.url).Check(context.Background())
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Check = %v, want error %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestTCPDialCheck(t *testing.T) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	open := l.Addr().String()
+
+	closed, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	closedAddr := closed.Addr().String()
+	_ = closed.Close()
+	t.Cleanup(func() { _ = l.Close() })
+
+	if err := TCPDialCheck(open).Check(context.Background()); err != nil {
+		t.Fatalf("open port: %v", err)
+	}
+	if err := TCPDialCheck(closedAddr).Check(context.Background()); err == nil {
+		t.Fatal("closed port: no error")
+	}
+}
@@ -0,0 +1,23 @@
+// Package httpclient builds clients for other services that carry the request
+// ID and the trace context of the caller.
+package httpclient
+
+import (
+	"net/http"
+	"time"
+
+	"example/pkg/requestid"
+	"example/pkg/tracing"
+)
+
+// New returns a client with the given timeout, 0 means none, sending requests
+// through Transport(nil).
+func New(timeout time.Duration) *http.Client {
+	return &http.Client{Timeout: timeout, Transport: Transport(nil)}
+}
+
+// Transport wraps base, http.DefaultTransport if nil, to forward the request
+// ID and start a client span for every request.
+func Transport(base http.RoundTripper) http.RoundTripper {
+	return requestid.NewTransport(tracing.NewTransport(base))
+}
@@ -0,0 +1,36 @@
+package httpclient
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"example/pkg/requestid"
+)
+
+func TestNewForwardsRequestID(t *testing.T) {
+	var got string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r.Header.Get(requestid.Header)
+	}))
+	t.Cleanup(srv.Close)
+
+	ctx := requestid.NewContext(context.Background(), "req-1")
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, srv.URL, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp, err := New(0).Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+
+	if got != "req-1" {
+		t.Fatalf("%s = %q, want req-1", requestid.Header, got)
+	}
+	if req.Header.Get(requestid.Header) != "" {
+		t.Fatal("the caller's request was modified")
+	}
+}
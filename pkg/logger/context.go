@@ -2,7 +2,10 @@ package logger
 
 import (
 	"context"
+	"sort"
+	"sync"
 
+	"go.opentelemetry.io/otel/trace"
 	"go.uber.org/zap"
 )
 
@@ -13,20 +16,98 @@ var (
 	httpContextKey = &contextKey{}
 )
 
+type bagContextKey struct{}
+
+// fieldBag collects the fields added further down the handler chain, which
+// the context of an outer middleware can't see otherwise.
+type fieldBag struct {
+	m      sync.Mutex
+	fields map[string]interface{}
+}
+
 func FromContext(ctx context.Context) *zap.SugaredLogger {
 	l := global
 
 	l = loggerWithHTTPContext(ctx, l)
+	if sc := trace.SpanContextFromContext(ctx); sc.IsValid() {
+		l = l.With("trace_id", sc.TraceID().String(), "span_id", sc.SpanID().String())
+	}
 	return l
 }
 
+// WithFields returns a context whose logger adds the key-value pairs kvs to
+// every entry, on top of the fields already in ctx.
+func WithFields(ctx context.Context, kvs ...interface{}) context.Context {
+	old, _ := ctx.Value(httpContextKey).(map[string]interface{})
+	m := make(map[string]interface{}, len(old)+len(kvs)/2)
+	for k, v := range old {
+		m[k] = v
+	}
+	for i := 0; i+1 < len(kvs); i += 2 {
+		if k, ok := kvs[i].(string); ok {
+			m[k] = kvs[i+1]
+		}
+	}
+	return context.WithValue(ctx, httpContextKey, m)
+}
+
+// WithFieldBag returns a context whose logger also has the fields that
+// handlers called with it, or with a context derived from it, add with
+// AddFields, e.g. for an access log written after the handler returns.
+func WithFieldBag(ctx context.Context) context.Context {
+	return context.WithValue(ctx, bagContextKey{}, &fieldBag{fields: make(map[string]interface{})})
+}
+
+// AddFields is WithFields that also adds kvs to the field bag of ctx, if any.
+func AddFields(ctx context.Context, kvs ...interface{}) context.Context {
+	if b, ok := ctx.Value(bagContextKey{}).(*fieldBag); ok {
+		b.m.Lock()
+		for i := 0; i+1 < len(kvs); i += 2 {
+			if k, ok := kvs[i].(string); ok {
+				b.fields[k] = kvs[i+1]
+			}
+		}
+		b.m.Unlock()
+	}
+	return WithFields(ctx, kvs...)
+}
+
+// Fields returns the fields added to ctx with WithFields and to its field bag.
+func Fields(ctx context.Context) map[string]interface{} {
+	m, _ := ctx.Value(httpContextKey).(map[string]interface{})
+	b, ok := ctx.Value(bagContextKey{}).(*fieldBag)
+	if !ok {
+		return m
+	}
+
+	b.m.Lock()
+	defer b.m.Unlock()
+	if len(b.fields) == 0 {
+		return m
+	}
+	all := make(map[string]interface{}, len(m)+len(b.fields))
+	for k, v := range b.fields {
+		all[k] = v
+	}
+	for k, v := range m {
+		all[k] = v
+	}
+	return all
+}
+
 func loggerWithHTTPContext(ctx context.Context, l *zap.SugaredLogger) *zap.SugaredLogger {
-	if m, ok := ctx.Value(httpContextKey).(map[string]interface{}); ok {
-		for k, v := range m {
-			l = l.With(k, v)
+	if m := Fields(ctx); len(m) > 0 {
+		keys := make([]string, 0, len(m))
+		for k := range m {
+			keys = append(keys, k)
 		}
-		return l
+		sort.Strings(keys)
 
+		kvs := make([]interface{}, 0, 2*len(keys))
+		for _, k := range keys {
+			kvs = append(kvs, k, m[k])
+		}
+		return l.With(kvs...)
 	}
 	return l
 }
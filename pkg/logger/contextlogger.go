@@ -0,0 +1,40 @@
+package logger
+
+import (
+	"context"
+)
+
+// ContextLogger logs an event with key-value pairs and the fields of ctx,
+// e.g. the request fields added by the HTTP middleware.
+type ContextLogger interface {
+	DebugKV(ctx context.Context, event string, kvs ...interface{})
+	InfoKV(ctx context.Context, event string, kvs ...interface{})
+	WarnKV(ctx context.Context, event string, kvs ...interface{})
+	ErrorKV(ctx context.Context, event string, kvs ...interface{})
+}
+
+type contextLogger struct {
+	name string
+}
+
+// NewContextLogger returns a ContextLogger writing to the global logger named
+// name, see Named.
+func NewContextLogger(name string) ContextLogger {
+	return contextLogger{name: name}
+}
+
+func (l contextLogger) DebugKV(ctx context.Context, event string, kvs ...interface{}) {
+	FromContext(ctx).Named(l.name).Debugw(event, kvs...)
+}
+
+func (l contextLogger) InfoKV(ctx context.Context, event string, kvs ...interface{}) {
+	FromContext(ctx).Named(l.name).Infow(event, kvs...)
+}
+
+func (l contextLogger) WarnKV(ctx context.Context, event string, kvs ...interface{}) {
+	FromContext(ctx).Named(l.name).Warnw(event, kvs...)
+}
+
+func (l contextLogger) ErrorKV(ctx context.Context, event string, kvs ...interface{}) {
+	FromContext(ctx).Named(l.name).Errorw(event, kvs...)
+}
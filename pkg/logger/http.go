@@ -0,0 +1,95 @@
+package logger
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"go.uber.org/zap/zapcore"
+)
+
+type levelState struct {
+	Level   string            `json:"level"`
+	Loggers map[string]string `json:"loggers"`
+}
+
+// levelRequest changes the global level, or the level of Logger if set. An
+// empty Level removes the override of Logger. With RevertAfter, e.g. "15m",
+// the previous level comes back after that time.
+type levelRequest struct {
+	Level       string `json:"level"`
+	Logger      string `json:"logger"`
+	RevertAfter string `json:"revert_after"`
+}
+
+// LevelHandler serves the log levels on GET and changes them on PUT.
+func LevelHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+		case http.MethodPut:
+			if err := changeLevel(r); err != nil {
+				writeJSON(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+				return
+			}
+		default:
+			w.Header().Set("Allow", "GET, PUT")
+			writeJSON(w, http.StatusMethodNotAllowed, map[string]string{"error": "method not allowed"})
+			return
+		}
+
+		state := levelState{Level: GetLevel().String(), Loggers: make(map[string]string)}
+		for name, l := range NamedLevels() {
+			state.Loggers[name] = l.String()
+		}
+		writeJSON(w, http.StatusOK, state)
+	})
+}
+
+func changeLevel(r *http.Request) error {
+	var req levelRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return fmt.Errorf("invalid body: %v", err)
+	}
+
+	var revertAfter time.Duration
+	if req.RevertAfter != "" {
+		var err error
+		revertAfter, err = time.ParseDuration(req.RevertAfter)
+		if err != nil || revertAfter <= 0 {
+			return fmt.Errorf("invalid revert_after %q", req.RevertAfter)
+		}
+	}
+
+	var l *zapcore.Level
+	switch {
+	case req.Level != "":
+		l = new(zapcore.Level)
+		if err := l.Set(req.Level); err != nil {
+			return err
+		}
+	case req.Logger == "":
+		return fmt.Errorf("level is required")
+	}
+
+	levels.set(req.Logger, l, revertAfter)
+
+	switch {
+	case l == nil:
+		Warnf(context.Background(), "Logger: level override of %q removed", req.Logger)
+	case revertAfter > 0:
+		Warnf(context.Background(), "Logger: level of %q set to %s for %s", displayName(req.Logger), l, revertAfter)
+	default:
+		Warnf(context.Background(), "Logger: level of %q set to %s", displayName(req.Logger), l)
+	}
+	return nil
+}
+
+func writeJSON(w http.ResponseWriter, code int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(code)
+	_ = json.NewEncoder(w).Encode(v)
+}
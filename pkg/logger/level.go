@@ -0,0 +1,205 @@
+package logger
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+var levels = &levelControl{reverts: make(map[string]*levelRevert)}
+
+// levelControl holds per-named-logger level overrides and pending reverts of
+// temporary level changes. The empty name is the global level.
+type levelControl struct {
+	// named maps a logger name to its level, it is replaced on every change
+	// so cores read it without locking.
+	named atomic.Pointer[map[string]zapcore.Level]
+
+	m       sync.Mutex
+	reverts map[string]*levelRevert
+}
+
+type levelRevert struct {
+	timer *time.Timer
+	// prev is the level before the first temporary change, nil for no override.
+	prev *zapcore.Level
+}
+
+// GetLevel returns the global level.
+func GetLevel() zapcore.Level {
+	return level.Level()
+}
+
+// Named returns the global logger named name. Its level can be overridden
+// with SetNamedLevel, nested names like "mysql.replica" inherit the override
+// of "mysql".
+func Named(name string) *zap.SugaredLogger {
+	// global skips a frame for the package level functions, Named is used directly.
+	return global.WithOptions(zap.AddCallerSkip(-1)).Named(name)
+}
+
+// SetNamedLevel overrides the level of the loggers named name.
+func SetNamedLevel(name string, l string) error {
+	var zapLevel zapcore.Level
+	if err := zapLevel.Set(l); err != nil {
+		return err
+	}
+	levels.set(name, &zapLevel, 0)
+	return nil
+}
+
+// ResetNamedLevel removes the override of the loggers named name.
+func ResetNamedLevel(name string) {
+	levels.set(name, nil, 0)
+}
+
+// NamedLevels returns the overridden levels by logger name.
+func NamedLevels() map[string]zapcore.Level {
+	named := levels.named.Load()
+	if named == nil {
+		return map[string]zapcore.Level{}
+	}
+	res := make(map[string]zapcore.Level, len(*named))
+	for k, v := range *named {
+		res[k] = v
+	}
+	return res
+}
+
+// set changes the level of name, nil removes a named override. With
+// revertAfter > 0 the previous level is restored after that time.
+func (c *levelControl) set(name string, l *zapcore.Level, revertAfter time.Duration) {
+	c.m.Lock()
+	defer c.m.Unlock()
+
+	prev := c.get(name)
+	if r, ok := c.reverts[name]; ok {
+		r.timer.Stop()
+		delete(c.reverts, name)
+		if revertAfter > 0 {
+			prev = r.prev
+		}
+	}
+
+	c.apply(name, l)
+
+	if revertAfter > 0 {
+		r := &levelRevert{prev: prev}
+		r.timer = time.AfterFunc(revertAfter, func() {
+			c.m.Lock()
+			defer c.m.Unlock()
+
+			if c.reverts[name] != r {
+				return
+			}
+			delete(c.reverts, name)
+			c.apply(name, r.prev)
+			Warnf(context.Background(), "Logger: level of %q reverted", displayName(name))
+		})
+		c.reverts[name] = r
+	}
+}
+
+func (c *levelControl) get(name string) *zapcore.Level {
+	if name == "" {
+		l := level.Level()
+		return &l
+	}
+	if named := c.named.Load(); named != nil {
+		if l, ok := (*named)[name]; ok {
+			return &l
+		}
+	}
+	return nil
+}
+
+func (c *levelControl) apply(name string, l *zapcore.Level) {
+	if name == "" {
+		if l != nil {
+			level.SetLevel(*l)
+		}
+		return
+	}
+
+	named := make(map[string]zapcore.Level)
+	if old := c.named.Load(); old != nil {
+		for k, v := range *old {
+			named[k] = v
+		}
+	}
+	if l == nil {
+		delete(named, name)
+	} else {
+		named[name] = *l
+	}
+	c.named.Store(&named)
+}
+
+// enabled reports whether lvl is enabled for the logger name: the override of
+// the name or of its closest parent wins over def.
+func (c *levelControl) enabled(name string, lvl zapcore.Level, def zapcore.LevelEnabler) bool {
+	named := c.named.Load()
+	if named == nil || len(*named) == 0 {
+		return def.Enabled(lvl)
+	}
+	for n := name; n != ""; {
+		if l, ok := (*named)[n]; ok {
+			return l.Enabled(lvl)
+		}
+		i := strings.LastIndexByte(n, '.')
+		if i < 0 {
+			break
+		}
+		n = n[:i]
+	}
+	return def.Enabled(lvl)
+}
+
+// anyEnabled reports whether lvl may be enabled for some logger.
+func (c *levelControl) anyEnabled(lvl zapcore.Level, def zapcore.LevelEnabler) bool {
+	if def.Enabled(lvl) {
+		return true
+	}
+	if named := c.named.Load(); named != nil {
+		for _, l := range *named {
+			if l.Enabled(lvl) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func displayName(name string) string {
+	if name == "" {
+		return "global"
+	}
+	return name
+}
+
+// levelCore filters entries by the level of their logger name. The wrapped
+// core must enable every level.
+type levelCore struct {
+	zapcore.Core
+	lvl zapcore.LevelEnabler
+}
+
+func (c *levelCore) Enabled(lvl zapcore.Level) bool {
+	return levels.anyEnabled(lvl, c.lvl)
+}
+
+func (c *levelCore) With(fields []zapcore.Field) zapcore.Core {
+	return &levelCore{Core: c.Core.With(fields), lvl: c.lvl}
+}
+
+func (c *levelCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if !levels.enabled(ent.LoggerName, ent.Level, c.lvl) {
+		return ce
+	}
+	return c.Core.Check(ent, ce)
+}
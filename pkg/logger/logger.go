@@ -2,7 +2,6 @@ package logger
 
 import (
 	"context"
-	"os"
 
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
@@ -17,44 +16,55 @@ func init() {
 	SetLogger(New(level))
 }
 
+// New returns a JSON logger writing entries enabled by lvl, the global level
+// if nil, to stdout. See NewWithOptions for other outputs.
 func New(lvl zapcore.LevelEnabler, options ...zap.Option) *zap.SugaredLogger {
+	return NewWithOptions(lvl, WithZapOptions(options...))
+}
+
+// NewWithOptions returns a logger writing entries enabled by lvl, the global
+// level if nil, to the sinks in opts, stdout by default.
+func NewWithOptions(lvl zapcore.LevelEnabler, opts ...Option) *zap.SugaredLogger {
 	if lvl == nil {
 		lvl = level
 	}
-	sink := zapcore.AddSync(os.Stdout)
-	options = append(options, zap.AddCallerSkip(1), zap.AddCaller())
-	return zap.New(
-		zapcore.NewCore(
-			zapcore.NewJSONEncoder(zapcore.EncoderConfig{
-				TimeKey:        "@timestamp",
-				LevelKey:       "level",
-				NameKey:        "logger",
-				CallerKey:      "caller",
-				MessageKey:     "message",
-				StacktraceKey:  "stacktrace",
-				LineEnding:     zapcore.DefaultLineEnding,
-				EncodeLevel:    zapcore.LowercaseColorLevelEncoder,
-				EncodeTime:     zapcore.ISO8601TimeEncoder,
-				EncodeDuration: zapcore.SecondsDurationEncoder,
-				EncodeCaller:   zapcore.ShortCallerEncoder,
-			}),
-			sink,
-			lvl,
-		),
-		options...,
-	).Sugar()
+	o := initOptions(opts)
+
+	// levelCore filters by lvl and the per-name overrides.
+	all := zap.LevelEnablerFunc(func(zapcore.Level) bool { return true })
+	cores := make([]zapcore.Core, 0, len(o.sinks))
+	for _, s := range o.sinks {
+		cores = append(cores, zapcore.NewCore(o.encoder(s), s.ws, all))
+	}
+
+	core := zapcore.NewTee(cores...)
+	if o.redact != nil {
+		core = &redactCore{Core: core, p: o.redact}
+	}
+	if o.sampling != nil {
+		core = zapcore.NewSamplerWithOptions(core, o.tick, o.sampling.Initial, o.sampling.Thereafter)
+	}
+
+	zapOptions := append(o.zapOptions, zap.AddCallerSkip(1), zap.AddCaller())
+	if len(o.fields) > 0 {
+		zapOptions = append(zapOptions, zap.Fields(o.fields...))
+	}
+	return zap.New(&levelCore{Core: core, lvl: lvl}, zapOptions...).Sugar()
 }
 
 func Level() *zap.SugaredLogger {
 	return global
 }
 
+// SetLevel sets the global level, an unknown level sets WARN. A pending
+// revert of the global level is cancelled.
 func SetLevel(l string) {
 	var zapLevel zapcore.Level
 	if err := zapLevel.Set(l); err != nil {
-		Warnf(context.Background(), "failed  parse log level %#q: %s, setting to WARN", level, err)
+		Warnf(context.Background(), "failed  parse log level %#q: %s, setting to WARN", l, err)
 		zapLevel = zapcore.WarnLevel
 	}
+	levels.set("", &zapLevel, 0)
 }
 
 func Logger() *zap.SugaredLogger {
@@ -123,4 +133,4 @@ func Fatalf(ctx context.Context, format string, args ...interface{}) {
 
 func FatalKV(ctx context.Context, message string, kvs ...interface{}) {
 	FromContext(ctx).Fatalw(message, kvs...)
-}
\ No newline at end of file
+}
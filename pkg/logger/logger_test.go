@@ -0,0 +1,29 @@
+package logger_test
+
+import (
+	"testing"
+
+	"example/pkg/logger"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestNewAppliesZapOptions(t *testing.T) {
+	observed, logs := observer.New(zapcore.DebugLevel)
+
+	l := logger.New(zapcore.InfoLevel,
+		zap.WrapCore(func(zapcore.Core) zapcore.Core { return observed }),
+		zap.Fields(zap.String("app", "test")),
+	)
+	l.Info("kept")
+
+	entries := logs.All()
+	if len(entries) != 1 || entries[0].Message != "kept" {
+		t.Fatalf("entries = %+v", entries)
+	}
+	if got := entries[0].ContextMap()["app"]; got != "test" {
+		t.Fatalf("app = %v, want test", got)
+	}
+}
@@ -0,0 +1,98 @@
+// Package loggertest provides a logger.ContextLogger that keeps entries in
+// memory, so tests can assert on what was logged.
+package loggertest
+
+import (
+	"context"
+	"sync"
+
+	"example/pkg/logger"
+
+	"go.uber.org/zap/zapcore"
+)
+
+type Entry struct {
+	Level zapcore.Level
+	Event string
+	// Fields holds the fields of the context and the key-value pairs.
+	Fields map[string]interface{}
+}
+
+type Logger struct {
+	m       sync.Mutex
+	entries []Entry
+}
+
+var _ logger.ContextLogger = (*Logger)(nil)
+
+func New() *Logger {
+	return &Logger{}
+}
+
+func (l *Logger) DebugKV(ctx context.Context, event string, kvs ...interface{}) {
+	l.add(ctx, zapcore.DebugLevel, event, kvs)
+}
+
+func (l *Logger) InfoKV(ctx context.Context, event string, kvs ...interface{}) {
+	l.add(ctx, zapcore.InfoLevel, event, kvs)
+}
+
+func (l *Logger) WarnKV(ctx context.Context, event string, kvs ...interface{}) {
+	l.add(ctx, zapcore.WarnLevel, event, kvs)
+}
+
+func (l *Logger) ErrorKV(ctx context.Context, event string, kvs ...interface{}) {
+	l.add(ctx, zapcore.ErrorLevel, event, kvs)
+}
+
+// Entries returns all entries in the order they were logged.
+func (l *Logger) Entries() []Entry {
+	l.m.Lock()
+	defer l.m.Unlock()
+
+	return append([]Entry(nil), l.entries...)
+}
+
+// Events returns the entries with the given event.
+func (l *Logger) Events(event string) []Entry {
+	var res []Entry
+	for _, e := range l.Entries() {
+		if e.Event == event {
+			res = append(res, e)
+		}
+	}
+	return res
+}
+
+// AtLevel returns the entries logged at lvl.
+func (l *Logger) AtLevel(lvl zapcore.Level) []Entry {
+	var res []Entry
+	for _, e := range l.Entries() {
+		if e.Level == lvl {
+			res = append(res, e)
+		}
+	}
+	return res
+}
+
+func (l *Logger) Reset() {
+	l.m.Lock()
+	l.entries = nil
+	l.m.Unlock()
+}
+
+func (l *Logger) add(ctx context.Context, lvl zapcore.Level, event string, kvs []interface{}) {
+	fields := make(map[string]interface{})
+	for k, v := range logger.Fields(ctx) {
+		fields[k] = v
+	}
+	for i := 0; i+1 < len(kvs); i += 2 {
+		if k, ok := kvs[i].(string); ok {
+			fields[k] = kvs[i+1]
+		}
+	}
+
+	l.m.Lock()
+	l.entries = append(l.entries, Entry{Level: lvl, Event: event, Fields: fields})
+	l.m.Unlock()
+}
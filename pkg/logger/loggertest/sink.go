@@ -0,0 +1,51 @@
+package loggertest
+
+import (
+	"bytes"
+	"sync"
+	"testing"
+
+	"example/pkg/logger"
+)
+
+// Sink is an in-memory zapcore.WriteSyncer to pass to logger.WithOutput.
+type Sink struct {
+	m   sync.Mutex
+	buf bytes.Buffer
+}
+
+func NewSink() *Sink {
+	return &Sink{}
+}
+
+func (s *Sink) Write(p []byte) (int, error) {
+	s.m.Lock()
+	defer s.m.Unlock()
+	return s.buf.Write(p)
+}
+
+func (s *Sink) Sync() error { return nil }
+
+func (s *Sink) String() string {
+	s.m.Lock()
+	defer s.m.Unlock()
+	return s.buf.String()
+}
+
+// PII lists the patterns AssertNoPII looks for.
+var PII = []logger.RedactPattern{logger.PatternEmail, logger.PatternBearer, logger.PatternJWT, logger.PatternCard}
+
+// AssertNoPII fails t for every email, bearer token, JWT or card number in
+// what was written to s.
+func AssertNoPII(t testing.TB, s *Sink) {
+	t.Helper()
+
+	out := s.String()
+	for _, p := range PII {
+		for _, m := range p.Re.FindAllString(out, -1) {
+			if p.Valid == nil || p.Valid(m) {
+				t.Errorf("log output contains %s %q", p.Name, m)
+			}
+		}
+	}
+}
@@ -0,0 +1,143 @@
+package logger
+
+import (
+	"os"
+	"time"
+
+	"github.com/mattn/go-isatty"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"gopkg.in/natefinch/lumberjack.v2"
+)
+
+const (
+	EncodingJSON    = "json"
+	EncodingConsole = "console"
+)
+
+type options struct {
+	encoding   string
+	color      *bool
+	sinks      []sink
+	sampling   *zap.SamplingConfig
+	tick       time.Duration
+	fields     []zap.Field
+	redact     *RedactPolicy
+	zapOptions []zap.Option
+}
+
+type sink struct {
+	ws  zapcore.WriteSyncer
+	tty bool
+}
+
+type Option func(*options)
+
+func initOptions(opts []Option) *options {
+	o := &options{encoding: EncodingJSON}
+	for i := range opts {
+		opts[i](o)
+	}
+	if len(o.sinks) == 0 {
+		o.sinks = []sink{newSink(os.Stdout)}
+	}
+	return o
+}
+
+// WithEncoding selects json or console output. JSON is the default.
+func WithEncoding(encoding string) Option {
+	return func(o *options) {
+		o.encoding = encoding
+	}
+}
+
+// WithColor forces colored levels on or off for the console encoding. By
+// default they are colored when the sink is a terminal. JSON is never colored.
+func WithColor(color bool) Option {
+	return func(o *options) {
+		o.color = &color
+	}
+}
+
+// WithOutput adds sinks, stdout is used when there are none.
+func WithOutput(ws ...zapcore.WriteSyncer) Option {
+	return func(o *options) {
+		for _, w := range ws {
+			o.sinks = append(o.sinks, newSink(w))
+		}
+	}
+}
+
+// WithFile adds a sink writing to path, rotated when it grows over maxSizeMB.
+// At most maxBackups rotated files younger than maxAgeDays are kept, zero
+// keeps all of them.
+func WithFile(path string, maxSizeMB, maxBackups, maxAgeDays int) Option {
+	return func(o *options) {
+		o.sinks = append(o.sinks, sink{ws: zapcore.AddSync(&lumberjack.Logger{
+			Filename:   path,
+			MaxSize:    maxSizeMB,
+			MaxBackups: maxBackups,
+			MaxAge:     maxAgeDays,
+			Compress:   true,
+		})})
+	}
+}
+
+// WithSampling logs the first entries with the same level and message every
+// tick, then only every thereafter-th one.
+func WithSampling(tick time.Duration, first, thereafter int) Option {
+	return func(o *options) {
+		o.tick = tick
+		o.sampling = &zap.SamplingConfig{Initial: first, Thereafter: thereafter}
+	}
+}
+
+// WithStaticFields adds fields to every entry, e.g. the app and host name.
+func WithStaticFields(fields ...zap.Field) Option {
+	return func(o *options) {
+		o.fields = append(o.fields, fields...)
+	}
+}
+
+func WithZapOptions(zapOptions ...zap.Option) Option {
+	return func(o *options) {
+		o.zapOptions = append(o.zapOptions, zapOptions...)
+	}
+}
+
+func newSink(ws zapcore.WriteSyncer) sink {
+	s := sink{ws: ws}
+	if f, ok := ws.(*os.File); ok {
+		s.tty = isatty.IsTerminal(f.Fd()) || isatty.IsCygwinTerminal(f.Fd())
+	}
+	return s
+}
+
+func (o *options) encoder(s sink) zapcore.Encoder {
+	cfg := zapcore.EncoderConfig{
+		TimeKey:        "@timestamp",
+		LevelKey:       "level",
+		NameKey:        "logger",
+		CallerKey:      "caller",
+		MessageKey:     "message",
+		StacktraceKey:  "stacktrace",
+		LineEnding:     zapcore.DefaultLineEnding,
+		EncodeLevel:    zapcore.LowercaseLevelEncoder,
+		EncodeTime:     zapcore.ISO8601TimeEncoder,
+		EncodeDuration: zapcore.SecondsDurationEncoder,
+		EncodeCaller:   zapcore.ShortCallerEncoder,
+	}
+
+	if o.encoding != EncodingConsole {
+		return zapcore.NewJSONEncoder(cfg)
+	}
+
+	color := s.tty
+	if o.color != nil {
+		color = *o.color
+	}
+	if color {
+		cfg.EncodeLevel = zapcore.LowercaseColorLevelEncoder
+	}
+	return zapcore.NewConsoleEncoder(cfg)
+}
@@ -0,0 +1,301 @@
+package logger
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+const redacted = "[REDACTED]"
+
+// RedactPattern matches values to redact. Valid, if set, filters out false
+// positives of Re, e.g. numbers that fail the card checksum.
+type RedactPattern struct {
+	Name  string
+	Re    *regexp.Regexp
+	Valid func(match string) bool
+}
+
+var (
+	PatternEmail = RedactPattern{
+		Name: "email",
+		Re:   regexp.MustCompile(`[A-Za-z0-9._%+-]+@[A-Za-z0-9.-]+\.[A-Za-z]{2,}`),
+	}
+	PatternBearer = RedactPattern{
+		Name: "bearer",
+		Re:   regexp.MustCompile(`(?i)bearer\s+[A-Za-z0-9\-._~+/]+=*`),
+	}
+	PatternJWT = RedactPattern{
+		Name: "jwt",
+		Re:   regexp.MustCompile(`eyJ[A-Za-z0-9_-]+\.[A-Za-z0-9_-]+\.[A-Za-z0-9_-]*`),
+	}
+	PatternCard = RedactPattern{
+		Name:  "card",
+		Re:    regexp.MustCompile(`\b\d(?:[ -]?\d){12,18}\b`),
+		Valid: luhn,
+	}
+)
+
+// RedactPolicy lists field names whose values are always redacted and
+// patterns redacted in messages and string values. A name matches whole
+// segments of a key, split at punctuation and camelCase, ignoring case: token
+// matches access_token and refreshToken but not tokens_used.
+type RedactPolicy struct {
+	Keys     []string
+	Patterns []RedactPattern
+}
+
+var (
+	baseRedactKeys = []string{"password", "passwd", "secret", "token", "authorization", "cookie", "api_key", "apikey", "private_key"}
+
+	// devEnvs keep emails readable, they only hold test data.
+	devEnvs = map[string]bool{"local": true, "dev": true, "development": true, "test": true}
+)
+
+// RedactPolicyFor returns the policy for the environment env: credentials,
+// tokens and card numbers are redacted everywhere, emails everywhere but in
+// local, dev and test environments.
+func RedactPolicyFor(env string) RedactPolicy {
+	p := RedactPolicy{
+		Keys:     append([]string(nil), baseRedactKeys...),
+		Patterns: []RedactPattern{PatternBearer, PatternJWT, PatternCard},
+	}
+	if !devEnvs[strings.ToLower(env)] {
+		p.Keys = append(p.Keys, "email")
+		p.Patterns = append(p.Patterns, PatternEmail)
+	}
+	return p
+}
+
+// WithRedaction redacts entries by p before they are encoded.
+func WithRedaction(p RedactPolicy) Option {
+	return func(o *options) {
+		o.redact = &p
+	}
+}
+
+func (p *RedactPolicy) key(k string) bool {
+	segs := keySegments(k)
+	for _, rk := range p.Keys {
+		if containsSegments(segs, keySegments(rk)) {
+			return true
+		}
+	}
+	return false
+}
+
+// keySegments splits k into lower case words, e.g. "X-Api-Key" into
+// [x api key] and "apiKey" into [api key].
+func keySegments(k string) []string {
+	var segs []string
+	var cur []rune
+	flush := func() {
+		if len(cur) > 0 {
+			segs = append(segs, strings.ToLower(string(cur)))
+			cur = cur[:0]
+		}
+	}
+
+	rs := []rune(k)
+	for i, r := range rs {
+		switch {
+		case !unicode.IsLetter(r) && !unicode.IsDigit(r):
+			flush()
+			continue
+		case unicode.IsUpper(r) && i > 0:
+			// A new word starts at aB and at the B of ABc.
+			prev := rs[i-1]
+			if unicode.IsLower(prev) || unicode.IsDigit(prev) ||
+				(unicode.IsUpper(prev) && i+1 < len(rs) && unicode.IsLower(rs[i+1])) {
+				flush()
+			}
+		}
+		cur = append(cur, r)
+	}
+	flush()
+	return segs
+}
+
+// containsSegments reports whether sub is a contiguous run of segs.
+func containsSegments(segs, sub []string) bool {
+	if len(sub) == 0 {
+		return false
+	}
+	for i := 0; i+len(sub) <= len(segs); i++ {
+		match := true
+		for j := range sub {
+			if segs[i+j] != sub[j] {
+				match = false
+				break
+			}
+		}
+		if match {
+			return true
+		}
+	}
+	return false
+}
+
+// Redact replaces the matches of the patterns in s.
+func (p *RedactPolicy) Redact(s string) string {
+	for _, pat := range p.Patterns {
+		if pat.Valid == nil {
+			s = pat.Re.ReplaceAllString(s, redacted)
+			continue
+		}
+		s = pat.Re.ReplaceAllStringFunc(s, func(m string) string {
+			if pat.Valid(m) {
+				return redacted
+			}
+			return m
+		})
+	}
+	return s
+}
+
+func (p *RedactPolicy) fields(fields []zapcore.Field) []zapcore.Field {
+	res := make([]zapcore.Field, len(fields))
+	for i, f := range fields {
+		res[i] = p.field(f)
+	}
+	return res
+}
+
+func (p *RedactPolicy) field(f zapcore.Field) zapcore.Field {
+	if p.key(f.Key) {
+		return zap.String(f.Key, redacted)
+	}
+
+	switch f.Type {
+	case zapcore.StringType:
+		return zap.String(f.Key, p.Redact(f.String))
+	case zapcore.ByteStringType:
+		return zap.String(f.Key, p.Redact(string(f.Interface.([]byte))))
+	case zapcore.ErrorType:
+		return zap.String(f.Key, p.Redact(f.Interface.(error).Error()))
+	case zapcore.StringerType:
+		return zap.String(f.Key, p.Redact(stringer(f.Interface)))
+	case zapcore.ObjectMarshalerType, zapcore.InlineMarshalerType:
+		enc := zapcore.NewMapObjectEncoder()
+		if err := f.Interface.(zapcore.ObjectMarshaler).MarshalLogObject(enc); err != nil {
+			return zap.String(f.Key, redacted)
+		}
+		if f.Type == zapcore.InlineMarshalerType {
+			return zap.Inline(redactedObject(p.value(enc.Fields).(map[string]interface{})))
+		}
+		return zap.Any(f.Key, p.value(enc.Fields))
+	case zapcore.ArrayMarshalerType, zapcore.ReflectType:
+		// Round trip through JSON to look into nested keys and values.
+		b, err := json.Marshal(arrayOrValue(f))
+		if err != nil {
+			return zap.String(f.Key, redacted)
+		}
+		var v interface{}
+		if err := json.Unmarshal(b, &v); err != nil {
+			return zap.String(f.Key, redacted)
+		}
+		return zap.Any(f.Key, p.value(v))
+	}
+	return f
+}
+
+func (p *RedactPolicy) value(v interface{}) interface{} {
+	switch v := v.(type) {
+	case string:
+		return p.Redact(v)
+	case map[string]interface{}:
+		res := make(map[string]interface{}, len(v))
+		for k, val := range v {
+			if p.key(k) {
+				res[k] = redacted
+				continue
+			}
+			res[k] = p.value(val)
+		}
+		return res
+	case []interface{}:
+		res := make([]interface{}, len(v))
+		for i := range v {
+			res[i] = p.value(v[i])
+		}
+		return res
+	}
+	return v
+}
+
+func stringer(v interface{}) (s string) {
+	defer func() {
+		if r := recover(); r != nil {
+			s = fmt.Sprintf("PANIC=%v", r)
+		}
+	}()
+	return v.(fmt.Stringer).String()
+}
+
+func arrayOrValue(f zapcore.Field) interface{} {
+	if f.Type != zapcore.ArrayMarshalerType {
+		return f.Interface
+	}
+	enc := zapcore.NewMapObjectEncoder()
+	_ = enc.AddArray("v", f.Interface.(zapcore.ArrayMarshaler))
+	return enc.Fields["v"]
+}
+
+type redactedObject map[string]interface{}
+
+func (o redactedObject) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	for k, v := range o {
+		if err := enc.AddReflected(k, v); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// luhn reports whether the digits of s pass the Luhn checksum of card numbers.
+func luhn(s string) bool {
+	var sum, n int
+	for i := len(s) - 1; i >= 0; i-- {
+		c := s[i]
+		if c < '0' || c > '9' {
+			continue
+		}
+		d := int(c - '0')
+		if n%2 == 1 {
+			d *= 2
+			if d > 9 {
+				d -= 9
+			}
+		}
+		sum += d
+		n++
+	}
+	return n >= 13 && sum%10 == 0
+}
+
+// redactCore redacts messages and fields before the wrapped core encodes them.
+type redactCore struct {
+	zapcore.Core
+	p *RedactPolicy
+}
+
+func (c *redactCore) With(fields []zapcore.Field) zapcore.Core {
+	return &redactCore{Core: c.Core.With(c.p.fields(fields)), p: c.p}
+}
+
+func (c *redactCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(ent.Level) {
+		return ce.AddCore(ent, c)
+	}
+	return ce
+}
+
+func (c *redactCore) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	ent.Message = c.p.Redact(ent.Message)
+	return c.Core.Write(ent, c.p.fields(fields))
+}
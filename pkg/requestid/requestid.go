@@ -0,0 +1,91 @@
+// Package requestid carries the ID correlating the logs and responses of a
+// request across services.
+package requestid
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"net/http"
+)
+
+const (
+	// Header carries the ID in HTTP requests and responses.
+	Header = "X-Request-ID"
+	// MetadataKey carries the ID in gRPC metadata.
+	MetadataKey = "x-request-id"
+
+	maxLen = 128
+)
+
+type contextKey struct{}
+
+func NewContext(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, contextKey{}, id)
+}
+
+// FromContext returns the request ID of ctx, "" if there is none.
+func FromContext(ctx context.Context) string {
+	id, _ := ctx.Value(contextKey{}).(string)
+	return id
+}
+
+// New returns a random request ID.
+func New() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// Valid reports whether an ID received from a client can be used as is: it
+// must be short and made of characters safe to log and echo in headers.
+func Valid(id string) bool {
+	if id == "" || len(id) > maxLen {
+		return false
+	}
+	for i := 0; i < len(id); i++ {
+		c := id[i]
+		switch {
+		case c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z', c >= '0' && c <= '9':
+		case c == '-' || c == '_' || c == '.' || c == ':' || c == '/' || c == '+' || c == '=':
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// Middleware takes the ID from the X-Request-ID header or generates one, puts
+// it into the request context and echoes it in the response.
+func Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := r.Header.Get(Header)
+		if !Valid(id) {
+			id = New()
+		}
+
+		w.Header().Set(Header, id)
+		next.ServeHTTP(w, r.WithContext(NewContext(r.Context(), id)))
+	})
+}
+
+// Transport forwards the request ID of the request context to the server.
+type Transport struct {
+	Base http.RoundTripper
+}
+
+// NewTransport wraps base, http.DefaultTransport if nil.
+func NewTransport(base http.RoundTripper) *Transport {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return &Transport{Base: base}
+}
+
+func (t *Transport) RoundTrip(r *http.Request) (*http.Response, error) {
+	if id := FromContext(r.Context()); id != "" && r.Header.Get(Header) == "" {
+		r = r.Clone(r.Context())
+		r.Header.Set(Header, id)
+	}
+	return t.Base.RoundTrip(r)
+}
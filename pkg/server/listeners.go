@@ -8,7 +8,9 @@ import (
 )
 
 type listeners struct {
-	publicHTTP net.Listener
+	publicHTTP     net.Listener
+	publicGRPC     net.Listener
+	monitoringHTTP net.Listener
 }
 
 func (s *Server) newListener() (*listeners, error) {
@@ -21,5 +23,34 @@ func (s *Server) newListener() (*listeners, error) {
 		}
 		l.publicHTTP = publicHTTP
 	}
+
+	if s.cfg.GrpcPort != nil {
+		publicGRPC, err := net.Listen("tcp", fmt.Sprintf(":%d", *s.cfg.GrpcPort))
+		if err != nil {
+			l.close()
+			return nil, errors.Wrap(err, "couldn't create public gRPC port listener")
+		}
+		l.publicGRPC = publicGRPC
+	}
+
+	monitoringHTTP, err := net.Listen("tcp", fmt.Sprintf(":%d", s.cfg.MonitoringPort))
+	if err != nil {
+		l.close()
+		return nil, errors.Wrap(err, "couldn't create monitoring HTTP port listener")
+	}
+	l.monitoringHTTP = monitoringHTTP
+
 	return l, nil
 }
+
+func (l *listeners) close() {
+	if l.publicHTTP != nil {
+		_ = l.publicHTTP.Close()
+	}
+	if l.publicGRPC != nil {
+		_ = l.publicGRPC.Close()
+	}
+	if l.monitoringHTTP != nil {
+		_ = l.monitoringHTTP.Close()
+	}
+}
@@ -0,0 +1,170 @@
+package grpc
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"example/pkg/logger"
+	"example/pkg/requestid"
+	"example/pkg/tracing"
+
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+type opNameKey struct{}
+
+func UnaryInterceptors(opts ...Option) []grpc.UnaryServerInterceptor {
+	o := initOptions(opts)
+
+	i := []grpc.UnaryServerInterceptor{defaultUnary(o.opNameFunc)}
+	if o.accessLog {
+		i = append(i, accessLogUnary)
+	}
+	i = append(i, RecoverUnary)
+
+	return append(i, o.customUnary...)
+}
+
+func StreamInterceptors(opts ...Option) []grpc.StreamServerInterceptor {
+	o := initOptions(opts)
+
+	i := []grpc.StreamServerInterceptor{defaultStream(o.opNameFunc)}
+	if o.accessLog {
+		i = append(i, accessLogStream)
+	}
+	i = append(i, RecoverStream)
+
+	return append(i, o.customStream...)
+}
+
+// OperationName returns the operation name resolved for the current call.
+func OperationName(ctx context.Context) string {
+	opName, _ := ctx.Value(opNameKey{}).(string)
+	return opName
+}
+
+// accessLogUnary and accessLogStream log every finished call with the
+// logger of its context.
+func accessLogUnary(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	t1 := time.Now()
+	resp, err := handler(ctx, req)
+	logFinished(logger.FromContext(ctx).Desugar(), info.FullMethod, t1, err)
+	return resp, err
+}
+
+func accessLogStream(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	t1 := time.Now()
+	err := handler(srv, ss)
+	logFinished(logger.FromContext(ss.Context()).Desugar(), info.FullMethod, t1, err)
+	return err
+}
+
+// WithLoggerUnary logs every finished call with l.
+//
+// Deprecated: use the WithAccessLog option, its log has the request fields.
+func WithLoggerUnary(l *zap.Logger) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		t1 := time.Now()
+		resp, err := handler(ctx, req)
+		logFinished(l, info.FullMethod, t1, err)
+		return resp, err
+	}
+}
+
+// WithLoggerStream logs every finished stream with l.
+//
+// Deprecated: use the WithAccessLog option, its log has the request fields.
+func WithLoggerStream(l *zap.Logger) grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		t1 := time.Now()
+		err := handler(srv, ss)
+		logFinished(l, info.FullMethod, t1, err)
+		return err
+	}
+}
+
+func logFinished(l *zap.Logger, method string, t1 time.Time, err error) {
+	code := status.Code(err)
+	l.Info(fmt.Sprintf("finished grpc request with code: %s", code),
+		zap.String("method", method),
+		zap.Duration("lat", time.Since(t1)),
+		zap.String("code", code.String()),
+	)
+}
+
+func defaultUnary(opNameFunc operationNameFunc) grpc.UnaryServerInterceptor {
+	if opNameFunc == nil {
+		opNameFunc = getOpName
+	}
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (resp interface{}, err error) {
+		ctx = withOpName(ctx, opNameFunc, info.FullMethod)
+		ctx = withRequestID(ctx, func(md metadata.MD) error { return grpc.SetHeader(ctx, md) })
+		ctx, span := startSpan(ctx, info.FullMethod)
+		defer func() { tracing.EndGRPC(span, err) }()
+
+		return handler(ctx, req)
+	}
+}
+
+func defaultStream(opNameFunc operationNameFunc) grpc.StreamServerInterceptor {
+	if opNameFunc == nil {
+		opNameFunc = getOpName
+	}
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) (err error) {
+		ctx := withOpName(ss.Context(), opNameFunc, info.FullMethod)
+		ctx = withRequestID(ctx, ss.SetHeader)
+		ctx, span := startSpan(ctx, info.FullMethod)
+		defer func() { tracing.EndGRPC(span, err) }()
+
+		return handler(srv, &wrappedStream{ServerStream: ss, ctx: ctx})
+	}
+}
+
+func withOpName(ctx context.Context, opNameFunc operationNameFunc, fullMethod string) context.Context {
+	opName := opNameFunc(ctx, fullMethod)
+	if opName == "" {
+		opName = getOpName(ctx, fullMethod)
+	}
+	return context.WithValue(ctx, opNameKey{}, opName)
+}
+
+// withRequestID takes the request ID from the metadata or generates one, sends
+// it back in the header and adds it and the operation name to the logger fields.
+func withRequestID(ctx context.Context, setHeader func(metadata.MD) error) context.Context {
+	id := requestid.FromIncomingContext(ctx)
+	_ = setHeader(metadata.Pairs(requestid.MetadataKey, id))
+
+	ctx = requestid.NewContext(ctx, id)
+	return logger.WithFields(ctx, "request_id", id, "operation", OperationName(ctx))
+}
+
+// startSpan starts a server span named with the operation name, continuing
+// the trace of the caller.
+func startSpan(ctx context.Context, fullMethod string) (context.Context, trace.Span) {
+	return tracing.Start(tracing.ExtractGRPC(ctx), OperationName(ctx),
+		trace.WithSpanKind(trace.SpanKindServer),
+		trace.WithAttributes(
+			semconv.RPCSystemGRPC,
+			semconv.RPCMethod(fullMethod),
+		),
+	)
+}
+
+func getOpName(_ context.Context, fullMethod string) string {
+	return "GRPC " + fullMethod
+}
+
+type wrappedStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (w *wrappedStream) Context() context.Context {
+	return w.ctx
+}
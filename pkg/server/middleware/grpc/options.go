@@ -0,0 +1,51 @@
+package grpc
+
+import (
+	"context"
+
+	"google.golang.org/grpc"
+)
+
+type options struct {
+	customUnary  []grpc.UnaryServerInterceptor
+	customStream []grpc.StreamServerInterceptor
+	opNameFunc   operationNameFunc
+	accessLog    bool
+}
+
+type operationNameFunc func(ctx context.Context, fullMethod string) string
+
+func initOptions(opts []Option) *options {
+	o := &options{}
+	for i := range opts {
+		opts[i](o)
+	}
+	return o
+}
+
+type Option func(*options)
+
+func WithCustomUnaryInterceptor(i ...grpc.UnaryServerInterceptor) Option {
+	return func(opts *options) {
+		opts.customUnary = i
+	}
+}
+
+func WithCustomStreamInterceptor(i ...grpc.StreamServerInterceptor) Option {
+	return func(opts *options) {
+		opts.customStream = i
+	}
+}
+
+func WithOperationNameFunc(f operationNameFunc) Option {
+	return func(opts *options) {
+		opts.opNameFunc = f
+	}
+}
+
+// WithAccessLog logs every call when it finishes.
+func WithAccessLog() Option {
+	return func(opts *options) {
+		opts.accessLog = true
+	}
+}
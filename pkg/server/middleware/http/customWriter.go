@@ -9,8 +9,9 @@ import (
 
 type customResponseWriter struct {
 	http.ResponseWriter
-	status int
-	size   int
+	status      int
+	size        int
+	wroteHeader bool
 }
 
 func newCustomerResponseWriter(w http.ResponseWriter) *customResponseWriter {
@@ -29,10 +30,12 @@ func (c *customResponseWriter) Status() int {
 
 func (c *customResponseWriter) WriteHeader(status int) {
 	c.status = status
+	c.wroteHeader = true
 	c.ResponseWriter.WriteHeader(status)
 }
 
 func (c *customResponseWriter) Write(b []byte) (int, error) {
+	c.wroteHeader = true
 	size, err := c.ResponseWriter.Write(b)
 	c.size += size
 	return size, err
@@ -2,15 +2,23 @@ package http
 
 import (
 	"compress/gzip"
+	"context"
 	"fmt"
+	"net"
 	"net/http"
 	"sync"
 	"time"
 
+	"example/pkg/logger"
+	"example/pkg/requestid"
+	"example/pkg/tracing"
+
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/chi/v5/middleware"
 	"github.com/go-chi/cors"
 	"github.com/go-chi/render"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
 	"go.uber.org/zap"
 )
 
@@ -20,18 +28,50 @@ func Middleware(opts ...Option) []func(http.Handler) http.Handler {
 	c := cors.New(o.corsOptions)
 
 	mw := []func(http.Handler) http.Handler{
+		requestid.Middleware,
 		defaultMiddleware(o.opNameFunc),
+	}
+	if o.accessLog {
+		// After the defaults, so the log has the request fields.
+		mw = append(mw, accessLog)
+	}
+	mw = append(mw,
+		Metrics,
 		mwGzipRequest,
 		c.Handler,
-		Recover,
+		recoverMiddleware(o.panicResponder),
 		middleware.URLFormat,
 		render.SetContentType(render.ContentTypeJSON),
-	}
+	)
 
 	mw = append(mw, o.customMiddleware...)
 	return mw
 }
 
+// accessLog logs every finished request with the logger of its context,
+// including the fields that later middleware add with logger.AddFields.
+func accessLog(next http.Handler) http.Handler {
+	fn := func(w http.ResponseWriter, r *http.Request) {
+		ww := middleware.NewWrapResponseWriter(w, r.ProtoMajor)
+		r = r.WithContext(logger.WithFieldBag(r.Context()))
+
+		t1 := time.Now()
+		defer func() {
+			logger.FromContext(r.Context()).Desugar().Info(fmt.Sprintf("finished http request with code: %d", ww.Status()),
+				zap.String("proto", r.Proto),
+				zap.String("path", r.URL.Path),
+				zap.Duration("lat", time.Since(t1)),
+				zap.Int("status", ww.Status()),
+			)
+		}()
+		next.ServeHTTP(ww, r)
+	}
+	return http.HandlerFunc(fn)
+}
+
+// WithLogger logs every finished request with l.
+//
+// Deprecated: use the WithAccessLog option, its log has the request fields.
 func WithLogger(l *zap.Logger) func(next http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
 		fn := func(w http.ResponseWriter, r *http.Request) {
@@ -52,6 +92,15 @@ func WithLogger(l *zap.Logger) func(next http.Handler) http.Handler {
 	}
 }
 
+type routeKey struct{}
+
+// RoutePattern returns the chi route pattern resolved for the current request,
+// "undefined" if no route matched.
+func RoutePattern(ctx context.Context) string {
+	route, _ := ctx.Value(routeKey{}).(string)
+	return route
+}
+
 func defaultMiddleware(opNameFunc operationNameFunc) func(http.Handler) http.Handler {
 	return func(h http.Handler) http.Handler {
 		return newWrapper(h, opNameFunc)
@@ -83,11 +132,21 @@ func newWrapper(h http.Handler, opNameFunc operationNameFunc) *defaultsWrapper {
 }
 
 func (h *defaultsWrapper) getOpName(r *http.Request) string {
-	var opName, handler string
+	var opName string
 	if r != nil {
-		opName, handler = "HTTP "+r.Method, ""
+		opName = "HTTP " + r.Method
 	}
 
+	if handler := h.routePattern(r); handler != "" {
+		opName += ": " + handler
+	}
+
+	return opName
+}
+
+// routePattern returns the chi pattern matching r, "undefined" if there is none.
+func (h *defaultsWrapper) routePattern(r *http.Request) string {
+	var handler string
 	if r != nil && r.Method != "OPTIONS" && r.URL != nil {
 		handler = "undefined"
 
@@ -119,11 +178,7 @@ func (h *defaultsWrapper) getOpName(r *http.Request) string {
 		h.pool.Put(pctx)
 	}
 
-	if handler != "" {
-		opName += ": " + handler
-	}
-
-	return opName
+	return handler
 }
 
 func (h *defaultsWrapper) ServeHTTP(w http.ResponseWriter, r *http.Request) {
@@ -132,7 +187,39 @@ func (h *defaultsWrapper) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		opName = h.getOpName(r)
 	}
 
-	h.next.ServeHTTP(w, r)
+	route, ip := h.routePattern(r), clientIP(r)
+
+	ctx, span := tracing.Start(tracing.ExtractHTTP(r.Context(), r), opName,
+		trace.WithSpanKind(trace.SpanKindServer),
+		trace.WithAttributes(
+			semconv.HTTPRequestMethodKey.String(r.Method),
+			semconv.HTTPRoute(route),
+			semconv.URLPath(r.URL.Path),
+			semconv.ClientAddress(ip),
+		),
+	)
+	defer span.End()
+
+	ctx = context.WithValue(ctx, routeKey{}, route)
+	ctx = logger.WithFields(ctx,
+		"request_id", requestid.FromContext(ctx),
+		"method", r.Method,
+		"route", route,
+		"operation", opName,
+		"client_ip", ip,
+	)
+
+	cw := newCustomerResponseWriter(w)
+	h.next.ServeHTTP(cw, r.WithContext(ctx))
+	tracing.SetHTTPStatus(span, cw.Status())
+}
+
+func clientIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
 }
 
 func mwGzipRequest(next http.Handler) http.Handler {
@@ -0,0 +1,82 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"example/pkg/logger"
+	"example/pkg/logger/loggertest"
+	"example/pkg/requestid"
+
+	"github.com/go-chi/chi/v5"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestAccessLogHasRequestFields(t *testing.T) {
+	sink := loggertest.NewSink()
+	prev := logger.Logger()
+	logger.SetLogger(logger.NewWithOptions(zapcore.DebugLevel, logger.WithOutput(sink)))
+	t.Cleanup(func() { logger.SetLogger(prev) })
+
+	router := chi.NewMux()
+	// Like auth.Middleware, which runs after the access log.
+	authenticate := func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			next.ServeHTTP(w, r.WithContext(logger.AddFields(r.Context(), "subject", "user-1")))
+		})
+	}
+	router.Use(Middleware(WithAccessLog(), WithCustomMiddleware(authenticate))...)
+	router.Get("/users/{uuid}", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusTeapot)
+	})
+
+	r := httptest.NewRequest(http.MethodGet, "/users/42", nil)
+	r.Header.Set(requestid.Header, "req-1")
+	router.ServeHTTP(httptest.NewRecorder(), r)
+
+	var entry map[string]interface{}
+	for _, line := range strings.Split(strings.TrimSpace(sink.String()), "\n") {
+		var e map[string]interface{}
+		if err := json.Unmarshal([]byte(line), &e); err != nil {
+			t.Fatalf("log line %q: %v", line, err)
+		}
+		if _, ok := e["lat"]; ok {
+			entry = e
+		}
+	}
+	if entry == nil {
+		t.Fatalf("no access log in %s", sink.String())
+	}
+
+	want := map[string]interface{}{
+		"request_id": "req-1",
+		"route":      "/users/{uuid}",
+		"method":     http.MethodGet,
+		"status":     float64(http.StatusTeapot),
+		"subject":    "user-1",
+	}
+	for k, v := range want {
+		if entry[k] != v {
+			t.Errorf("%s = %v, want %v", k, entry[k], v)
+		}
+	}
+}
+
+func TestWithLoggerStillLogs(t *testing.T) {
+	core, logs := observer.New(zapcore.InfoLevel)
+
+	h := WithLogger(zap.New(core))(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/x", nil))
+
+	entries := logs.FilterField(zap.Int("status", http.StatusAccepted)).All()
+	if len(entries) != 1 {
+		t.Fatalf("access log entries = %v", logs.All())
+	}
+}
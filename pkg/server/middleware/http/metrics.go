@@ -0,0 +1,90 @@
+package http
+
+import (
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var (
+	registerOnce sync.Once
+
+	requestsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "http",
+		Subsystem: "server",
+		Name:      "requests_total",
+		Help:      "Number of HTTP requests.",
+	}, []string{"method", "route", "status_class"})
+
+	requestDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: "http",
+		Subsystem: "server",
+		Name:      "request_duration_seconds",
+		Help:      "Duration of HTTP requests.",
+		Buckets:   []float64{.005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10},
+	}, []string{"method", "route", "status_class"})
+
+	requestsInFlight = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: "http",
+		Subsystem: "server",
+		Name:      "requests_in_flight",
+		Help:      "Number of HTTP requests being served.",
+	}, []string{"method", "route"})
+
+	responseSize = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: "http",
+		Subsystem: "server",
+		Name:      "response_size_bytes",
+		Help:      "Size of HTTP response bodies.",
+		Buckets:   prometheus.ExponentialBuckets(100, 10, 6),
+	}, []string{"method", "route", "status_class"})
+)
+
+// Metrics records RED metrics labeled by method, the route pattern resolved
+// by the default middleware and the status class, e.g. 2xx.
+func Metrics(next http.Handler) http.Handler {
+	registerOnce.Do(func() {
+		prometheus.MustRegister(requestsTotal, requestDuration, requestsInFlight, responseSize)
+	})
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		method := methodLabel(r.Method)
+		route := RoutePattern(r.Context())
+
+		inFlight := requestsInFlight.WithLabelValues(method, route)
+		inFlight.Inc()
+
+		cw := newCustomerResponseWriter(w)
+		started := time.Now()
+		defer func() {
+			inFlight.Dec()
+
+			class := statusClass(cw.Status())
+			requestsTotal.WithLabelValues(method, route, class).Inc()
+			requestDuration.WithLabelValues(method, route, class).Observe(time.Since(started).Seconds())
+			responseSize.WithLabelValues(method, route, class).Observe(float64(cw.size))
+		}()
+
+		next.ServeHTTP(cw, r)
+	})
+}
+
+func statusClass(status int) string {
+	if status < 100 || status > 599 {
+		return "other"
+	}
+	return strconv.Itoa(status/100) + "xx"
+}
+
+// methodLabel keeps the method label bounded for arbitrary client methods.
+func methodLabel(method string) string {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodPost, http.MethodPut, http.MethodPatch,
+		http.MethodDelete, http.MethodConnect, http.MethodOptions, http.MethodTrace:
+		return method
+	}
+	return "OTHER"
+}
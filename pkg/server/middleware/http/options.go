@@ -7,9 +7,11 @@ import (
 )
 
 type options struct {
-	corsOptions cors.Options
-	customMiddleware []func (http.Handler)  http.Handler
-	opNameFunc operationNameFunc
+	corsOptions      cors.Options
+	customMiddleware []func(http.Handler) http.Handler
+	opNameFunc       operationNameFunc
+	panicResponder   PanicResponder
+	accessLog        bool
 }
 
 type operationNameFunc func(*http.Request) string
@@ -25,7 +27,7 @@ func initOptions(opts []Option) *options {
 type Option func(*options)
 
 func WithCORSOptions(corsOpts cors.Options) Option {
-	return func (opts *options)  {
+	return func(opts *options) {
 		opts.corsOptions = corsOpts
 	}
 }
@@ -37,7 +39,22 @@ func WithCustomMiddleware(mw ...func(http.Handler) http.Handler) Option {
 }
 
 func WithOperationNameFunc(f operationNameFunc) Option {
-	return func (opts *options)  {
+	return func(opts *options) {
 		opts.opNameFunc = f
 	}
-}
\ No newline at end of file
+}
+
+// WithPanicResponder sets the response to requests whose handler panicked,
+// a bare 500 by default.
+func WithPanicResponder(f PanicResponder) Option {
+	return func(opts *options) {
+		opts.panicResponder = f
+	}
+}
+
+// WithAccessLog logs every request when it finishes.
+func WithAccessLog() Option {
+	return func(opts *options) {
+		opts.accessLog = true
+	}
+}
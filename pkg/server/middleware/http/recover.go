@@ -5,9 +5,41 @@ import (
 	"net/http"
 )
 
+// PanicResponder writes the response to a request whose handler panicked,
+// debugID is logged with the panic.
+type PanicResponder func(w http.ResponseWriter, r *http.Request, debugID string)
+
+// Recover reports panics of the next handlers and answers 500.
 func Recover(next http.Handler) http.Handler {
-	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		defer goruntime.RecoverPanic(r.Context(), "http-request-handling")
-		next.ServeHTTP(w, r)
-	})
+	return recoverMiddleware(nil)(next)
+}
+
+func recoverMiddleware(respond PanicResponder) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			cw := newCustomerResponseWriter(w)
+			defer func() {
+				p := recover()
+				if p == nil {
+					return
+				}
+				// The server aborts the response without logging it.
+				if p == http.ErrAbortHandler {
+					panic(p)
+				}
+
+				debugID := goruntime.HandlePanic(r.Context(), "http-request-handling", p)
+				if cw.wroteHeader {
+					return
+				}
+				if respond == nil {
+					w.WriteHeader(http.StatusInternalServerError)
+					return
+				}
+				respond(w, r, debugID)
+			}()
+
+			next.ServeHTTP(cw, r)
+		})
+	}
 }
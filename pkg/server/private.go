@@ -2,22 +2,59 @@ package server
 
 import (
 	"context"
+	"net/http"
 	"time"
 
 	"example/pkg/logger"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
 )
 
+func (s *Server) privateRouter() http.Handler {
+	router := chi.NewMux()
+	router.Use(middleware.Recoverer)
+
+	router.Method(http.MethodGet, "/live", s.health.LiveHandler())
+	router.Method(http.MethodGet, "/ready", s.health.ReadyHandler())
+	router.Handle("/metrics", promhttp.Handler())
+	router.Method(http.MethodGet, "/log/level", logger.LevelHandler())
+	router.Method(http.MethodPut, "/log/level", logger.LevelHandler())
+	router.Mount("/debug", middleware.Profiler())
+
+	return router
+}
+
+func (s *Server) runHTTPPrivate() {
+	s.monitoringHTTP = &http.Server{
+		Handler:           s.privateRouter(),
+		ReadTimeout:       readTimeout,
+		WriteTimeout:      writeTime,
+		ReadHeaderTimeout: readHeaderTimeout,
+	}
+
+	go func() {
+		err := s.monitoringHTTP.Serve(s.listeners.monitoringHTTP)
+		if err != nil && !errors.Is(err, http.ErrServerClosed) {
+			logger.Error(context.Background(), errors.Wrap(err, "httpPrivate private server").Error())
+			s.publicCloser.CloseAll()
+		}
+	}()
+}
+
 func (s *Server) closePrivate() error {
-	// if s.monitoringHTTP == nil {
-	// 	return errors.New("httpPrivate is nil")
-	// }
+	if s.monitoringHTTP == nil {
+		return errors.New("httpPrivate is nil")
+	}
 
-	_, cancel := context.WithTimeout(context.Background(), time.Second)
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
 	defer cancel()
-	// s.monitoringHTTP.SetKeepAlivesEnabled(false)
-	// if err := errors.Wrap(s.monitoringHTTP.Shutdown(ctx), "httpPrivate private server: error during shutdown"); err != nil {
-	// 	return err
-	// }
+	s.monitoringHTTP.SetKeepAlivesEnabled(false)
+	if err := errors.Wrap(s.monitoringHTTP.Shutdown(ctx), "httpPrivate private server: error during shutdown"); err != nil {
+		return err
+	}
 	logger.Warn(context.Background(), "httpPrivate private server: gracefully stopped")
 	return nil
 }
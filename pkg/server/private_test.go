@@ -0,0 +1,91 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"example/pkg/healthcheck"
+	"example/pkg/logger"
+
+	"github.com/pkg/errors"
+)
+
+func newTestServer() *Server {
+	return &Server{cfg: &Config{}, health: healthcheck.New()}
+}
+
+func serve(t *testing.T, h http.Handler, method, path, body string) *httptest.ResponseRecorder {
+	t.Helper()
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest(method, path, strings.NewReader(body)))
+	return w
+}
+
+func TestPrivateLogLevel(t *testing.T) {
+	prev := logger.GetLevel().String()
+	t.Cleanup(func() { logger.SetLevel(prev) })
+
+	h := newTestServer().privateRouter()
+
+	w := serve(t, h, http.MethodPut, "/log/level", `{"level": "warn"}`)
+	if w.Code != http.StatusOK {
+		t.Fatalf("PUT status = %d: %s", w.Code, w.Body)
+	}
+
+	w = serve(t, h, http.MethodGet, "/log/level", "")
+	var state struct {
+		Level string `json:"level"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &state); err != nil {
+		t.Fatalf("GET body %q: %v", w.Body, err)
+	}
+	if w.Code != http.StatusOK || state.Level != "warn" {
+		t.Fatalf("GET = %d %s, want warn", w.Code, w.Body)
+	}
+
+	for _, body := range []string{`{"level": "loud"}`, `{}`, `not json`, `{"level": "info", "revert_after": "soon"}`} {
+		if w := serve(t, h, http.MethodPut, "/log/level", body); w.Code != http.StatusBadRequest {
+			t.Errorf("PUT %s status = %d, want 400", body, w.Code)
+		}
+	}
+	if w := serve(t, h, http.MethodPost, "/log/level", `{"level": "info"}`); w.Code != http.StatusMethodNotAllowed {
+		t.Errorf("POST status = %d, want 405", w.Code)
+	}
+}
+
+func TestPrivateProbes(t *testing.T) {
+	s := newTestServer()
+	h := s.privateRouter()
+
+	var dbErr error
+	s.health.Register("db", healthcheck.CheckerFunc(func(context.Context) error { return dbErr }))
+
+	if w := serve(t, h, http.MethodGet, "/ready", ""); w.Code != http.StatusOK {
+		t.Fatalf("ready status = %d: %s", w.Code, w.Body)
+	}
+
+	dbErr = errors.New("connection refused")
+	w := serve(t, h, http.MethodGet, "/ready", "")
+	if w.Code != http.StatusServiceUnavailable || !strings.Contains(w.Body.String(), "connection refused") {
+		t.Fatalf("ready with a failing check = %d %s", w.Code, w.Body)
+	}
+	// The check is not a liveness check, a failing database must not restart the pod.
+	if w := serve(t, h, http.MethodGet, "/live", ""); w.Code != http.StatusOK {
+		t.Fatalf("live status = %d: %s", w.Code, w.Body)
+	}
+
+	dbErr = nil
+	s.health.Shutdown()
+	w = serve(t, h, http.MethodGet, "/ready", "")
+	if w.Code != http.StatusServiceUnavailable || !strings.Contains(w.Body.String(), string(healthcheck.StatusShuttingDown)) {
+		t.Fatalf("ready after shutdown = %d %s", w.Code, w.Body)
+	}
+
+	if w := serve(t, h, http.MethodGet, "/metrics", ""); w.Code != http.StatusOK {
+		t.Fatalf("metrics status = %d", w.Code)
+	}
+}
@@ -0,0 +1,73 @@
+package server
+
+import (
+	"context"
+	"time"
+
+	"example/pkg/logger"
+	mwgrpc "example/pkg/server/middleware/grpc"
+
+	"github.com/pkg/errors"
+	"google.golang.org/grpc"
+)
+
+type ServiceGRPC struct {
+	Desc    *grpc.ServiceDesc
+	Service interface{}
+}
+
+// newGRPCPublic builds the public gRPC server. It runs before the serving
+// goroutine starts, so closeGRPCPublic always sees it.
+func (s *Server) newGRPCPublic(services []ServiceGRPC) *grpc.Server {
+	var opts []mwgrpc.Option
+	if s.cfg.Logging {
+		opts = append(opts, mwgrpc.WithAccessLog())
+	}
+	unary := mwgrpc.UnaryInterceptors(opts...)
+	stream := mwgrpc.StreamInterceptors(opts...)
+
+	srv := grpc.NewServer(
+		grpc.ChainUnaryInterceptor(unary...),
+		grpc.ChainStreamInterceptor(stream...),
+	)
+
+	for i := range services {
+		srv.RegisterService(services[i].Desc, services[i].Service)
+	}
+
+	return srv
+}
+
+func (s *Server) runGRPCPublic() error {
+	if err := s.publicGRPC.Serve(s.listeners.publicGRPC); err != nil {
+		return errors.Wrap(err, "grpcPublic public server")
+	}
+
+	return nil
+}
+
+func (s *Server) closeGRPCPublic() error {
+	if s.publicGRPC == nil {
+		return errors.New("grpcPublic is nil")
+	}
+	s.health.Shutdown()
+	logger.Warn(context.Background(), "grpcPublic public server: waiting stop of traffic")
+	time.Sleep(s.gracefulDelay)
+	logger.Warn(context.Background(), "grpcPublic public server: shutting down")
+
+	stopped := make(chan struct{})
+	go func() {
+		s.publicGRPC.GracefulStop()
+		close(stopped)
+	}()
+
+	select {
+	case <-stopped:
+	case <-time.After(gracefulTimeOut):
+		s.publicGRPC.Stop()
+		return errors.New("grpcPublic public server: graceful stop timed out")
+	}
+	logger.Warn(context.Background(), "grpcPublic public server: stopped")
+
+	return nil
+}
@@ -3,6 +3,7 @@ package server
 import (
 	"context"
 	"example/pkg/logger"
+	"example/pkg/requestid"
 	mwhttp "example/pkg/server/middleware/http"
 	"net/http"
 	"time"
@@ -17,30 +18,33 @@ type RouterHTTP struct {
 	Handler chi.Router
 }
 
-func (s *Server) runHTTPPublic(r chi.Router) error {
+// newHTTPPublic builds the public HTTP server. Like newGRPCPublic it runs
+// before the serving goroutine starts, so closeHTTPPublic always sees it.
+func (s *Server) newHTTPPublic(r chi.Router) *http.Server {
 	router := chi.NewMux()
+
+	opts := []mwhttp.Option{
+		mwhttp.WithOperationNameFunc(nil),
+		mwhttp.WithPanicResponder(PanicJSON),
+		mwhttp.WithCORSOptions(cors.Options{
+			AllowedOrigins: []string{"*"},
+			AllowedMethods: []string{
+				http.MethodHead,
+				http.MethodGet,
+				http.MethodPost,
+				http.MethodPut,
+				http.MethodPatch,
+				http.MethodDelete,
+			},
+			AllowedHeaders:   []string{"*"},
+			ExposedHeaders:   []string{requestid.Header},
+			AllowCredentials: true,
+		}),
+	}
 	if s.cfg.Logging {
-		router.Use(mwhttp.WithLogger(logger.Logger().Desugar()))
+		opts = append(opts, mwhttp.WithAccessLog())
 	}
-
-	router.Use(
-		mwhttp.Middleware(
-			mwhttp.WithOperationNameFunc(nil),
-			mwhttp.WithCORSOptions(cors.Options{
-				AllowedOrigins: []string{"*"},
-				AllowedMethods: []string{
-					http.MethodHead,
-					http.MethodGet,
-					http.MethodPost,
-					http.MethodPut,
-					http.MethodPatch,
-					http.MethodDelete,
-				},
-				AllowedHeaders:   []string{"*"},
-				AllowCredentials: true,
-			}),
-		)...,
-	)
+	router.Use(mwhttp.Middleware(append(opts, s.cfg.HTTPOptions...)...)...)
 
 	if r != nil {
 		router.Mount("/", r)
@@ -50,14 +54,19 @@ func (s *Server) runHTTPPublic(r chi.Router) error {
 		router.HandleFunc("/", http.NotFound)
 	}
 
-	s.publicHTTP = &http.Server{
+	return &http.Server{
 		Handler:           router,
 		ReadTimeout:       readTimeout,
 		WriteTimeout:      writeTime,
 		ReadHeaderTimeout: readHeaderTimeout,
 	}
+}
 
-	//Add HealthCheck
+func (s *Server) runHTTPPublic() error {
+	err := s.publicHTTP.Serve(s.listeners.publicHTTP)
+	if err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return errors.Wrap(err, "httpPublic public server")
+	}
 
 	return nil
 }
@@ -66,13 +75,14 @@ func (s *Server) closeHTTPPublic() error {
 	if s.publicHTTP == nil {
 		return errors.New("httpPublic is nil")
 	}
-	ctx, cancel := context.WithTimeout(context.Background(), gracefulTimeOut)
-	defer cancel()
-	//add healthcheck
+	s.health.Shutdown()
 	logger.Warn(context.Background(), "httpPublic public server: waiting stop of traffic")
-	time.Sleep(gracefulDelay)
+	time.Sleep(s.gracefulDelay)
 	logger.Warn(context.Background(), "httpPublic public server: shutting down")
 
+	ctx, cancel := context.WithTimeout(context.Background(), gracefulTimeOut)
+	defer cancel()
+
 	s.publicHTTP.SetKeepAlivesEnabled(false)
 	if err := errors.Wrap(s.publicHTTP.Shutdown(ctx), "httpPublic public server: error shutdown"); err != nil {
 		return err
@@ -0,0 +1,112 @@
+package server
+
+import (
+	"net"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"google.golang.org/grpc"
+)
+
+const testGracefulDelay = 200 * time.Millisecond
+
+func listen(t *testing.T) net.Listener {
+	t.Helper()
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return l
+}
+
+// closeDuringDelay runs close and, while it waits for the graceful delay,
+// calls during. It returns how long close took.
+func closeDuringDelay(t *testing.T, close func() error, during func()) time.Duration {
+	t.Helper()
+	start := time.Now()
+	closed := make(chan error, 1)
+	go func() { closed <- close() }()
+
+	time.Sleep(testGracefulDelay / 4)
+	during()
+
+	if err := <-closed; err != nil {
+		t.Fatalf("close: %v", err)
+	}
+	return time.Since(start)
+}
+
+func assertNotReady(t *testing.T, s *Server) {
+	t.Helper()
+	if w := serve(t, s.health.ReadyHandler(), http.MethodGet, "/ready", ""); w.Code != http.StatusServiceUnavailable {
+		t.Errorf("ready during the graceful delay = %d, want 503", w.Code)
+	}
+}
+
+func TestCloseHTTPPublicKeepsServingDuringDelay(t *testing.T) {
+	s := newTestServer()
+	s.gracefulDelay = testGracefulDelay
+	l := listen(t)
+	s.listeners = &listeners{publicHTTP: l}
+
+	r := chi.NewRouter()
+	r.Get("/ping", func(w http.ResponseWriter, _ *http.Request) { w.WriteHeader(http.StatusNoContent) })
+	s.publicHTTP = s.newHTTPPublic(r)
+
+	served := make(chan error, 1)
+	go func() { served <- s.runHTTPPublic() }()
+
+	url := "http://" + l.Addr().String() + "/ping"
+	elapsed := closeDuringDelay(t, s.closeHTTPPublic, func() {
+		assertNotReady(t, s)
+		resp, err := http.Get(url)
+		if err != nil {
+			t.Errorf("request during the graceful delay: %v", err)
+			return
+		}
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusNoContent {
+			t.Errorf("request during the graceful delay = %d", resp.StatusCode)
+		}
+	})
+	if elapsed < testGracefulDelay {
+		t.Errorf("closed after %s, before the graceful delay of %s", elapsed, testGracefulDelay)
+	}
+	if err := <-served; err != nil {
+		t.Fatalf("runHTTPPublic: %v", err)
+	}
+}
+
+func TestCloseHTTPPublicBeforeServing(t *testing.T) {
+	s := newTestServer()
+	s.listeners = &listeners{publicHTTP: listen(t)}
+	s.publicHTTP = s.newHTTPPublic(nil)
+
+	// An early shutdown must not skip the server, it is built before serving.
+	if err := s.closeHTTPPublic(); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.runHTTPPublic(); err != nil {
+		t.Fatalf("runHTTPPublic after close: %v", err)
+	}
+}
+
+func TestCloseGRPCPublicWaitsForDelay(t *testing.T) {
+	s := newTestServer()
+	s.gracefulDelay = testGracefulDelay
+	s.listeners = &listeners{publicGRPC: listen(t)}
+	s.publicGRPC = s.newGRPCPublic(nil)
+
+	served := make(chan error, 1)
+	go func() { served <- s.runGRPCPublic() }()
+
+	elapsed := closeDuringDelay(t, s.closeGRPCPublic, func() { assertNotReady(t, s) })
+	if elapsed < testGracefulDelay {
+		t.Errorf("closed after %s, before the graceful delay of %s", elapsed, testGracefulDelay)
+	}
+	if err := <-served; err != nil && err != grpc.ErrServerStopped {
+		t.Fatalf("runGRPCPublic: %v", err)
+	}
+}
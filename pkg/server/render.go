@@ -1,11 +1,16 @@
 package server
 
 import (
+	"errors"
 	"net/http"
 
+	"example/pkg/requestid"
+
 	"github.com/go-chi/render"
 )
 
+var errInternal = errors.New("internal error")
+
 type httpError struct {
 	Meta meta `json:"meta"`
 }
@@ -41,10 +46,24 @@ func ResponseJSONWithCode(w http.ResponseWriter, r *http.Request, code int, obj
 }
 
 func ErrorJSON(w http.ResponseWriter, r *http.Request, code int, err error, errs ...ErrorDetail) {
+	errorJSON(w, r, code, err, "", errs...)
+}
+
+// PanicJSON answers a request whose handler panicked with 500 and the debug
+// ID the panic was logged with.
+func PanicJSON(w http.ResponseWriter, r *http.Request, debugID string) {
+	errorJSON(w, r, http.StatusInternalServerError, errInternal, debugID)
+}
+
+func errorJSON(w http.ResponseWriter, r *http.Request, code int, err error, debugID string, errs ...ErrorDetail) {
 	var resp httpError
 
 	resp.Meta.Code = code
 	resp.Meta.Message = err.Error()
+	resp.Meta.DebugID = debugID
+	if debugID == "" {
+		resp.Meta.DebugID = requestid.FromContext(r.Context())
+	}
 	resp.Meta.Errors = errs
 
 	render.Status(r, code)
@@ -3,7 +3,9 @@ package server
 import (
 	"context"
 	"example/pkg/closer"
+	"example/pkg/healthcheck"
 	"example/pkg/logger"
+	mwhttp "example/pkg/server/middleware/http"
 	"fmt"
 	"net/http"
 	"strings"
@@ -13,6 +15,7 @@ import (
 	"github.com/go-chi/chi/v5"
 	"github.com/pkg/errors"
 	httpSwagger "github.com/swaggo/http-swagger"
+	"google.golang.org/grpc"
 )
 
 const (
@@ -25,9 +28,16 @@ const (
 type Server struct {
 	cfg *Config
 
-	publicHTTP *http.Server
+	publicHTTP     *http.Server
+	publicGRPC     *grpc.Server
+	monitoringHTTP *http.Server
 
 	listeners *listeners
+	// health backs /live and /ready, healthcheck.Default() outside tests.
+	health *healthcheck.Registry
+	// gracefulDelay is how long a closing public server keeps serving after
+	// readiness fails, so the load balancer can stop sending traffic.
+	gracefulDelay time.Duration
 
 	publicCloser  *closer.Closer
 	privateCloser *closer.Closer
@@ -40,12 +50,25 @@ type Config struct {
 	Swagger bool
 	Logging bool
 
-	HTTPPort *uint
+	HTTPPort       *uint
+	GrpcPort       *uint
+	MonitoringPort uint
+
+	// HTTPOptions are applied after the defaults of the public HTTP middleware,
+	// e.g. mwhttp.WithCustomMiddleware.
+	HTTPOptions []mwhttp.Option
 }
 
 func New(cfg *Config) (*Server, error) {
+	if cfg.MonitoringPort == 0 {
+		cfg.MonitoringPort = monitoringHTTPDefault
+	}
+
 	s := &Server{
-		cfg: cfg,
+		cfg:    cfg,
+		health: healthcheck.Default(),
+
+		gracefulDelay: gracefulDelay,
 
 		publicCloser:  closer.New(syscall.SIGTERM, syscall.SIGINT),
 		privateCloser: closer.New(),
@@ -65,31 +88,45 @@ const (
 	gracefulTimeOut = 10 * time.Second
 )
 
-func (s *Server) Run(routers []RouterHTTP) {
-	logger.Errorf(context.Background(), "app started %s, env %s; %s", s.cfg.Name, s.cfg.Env, logPorts(s.cfg.HTTPPort, nil, 0))
+func (s *Server) Run(routers []RouterHTTP, services ...ServiceGRPC) {
+	logger.Errorf(context.Background(), "app started %s, env %s; %s", s.cfg.Name, s.cfg.Env, logPorts(s.cfg.HTTPPort, s.cfg.GrpcPort, s.cfg.MonitoringPort))
+
+	s.runHTTPPrivate()
+	s.privateCloser.Add(s.closePrivate)
 
 	if s.cfg.HTTPPort != nil {
-		go func(routers []RouterHTTP) {
-			r := chi.NewRouter()
-			if s.cfg.Swagger {
-				r.Get("/swagger/*", httpSwagger.Handler(httpSwagger.URL("doc.json")))
+		r := chi.NewRouter()
+		if s.cfg.Swagger {
+			r.Get("/swagger/*", httpSwagger.Handler(httpSwagger.URL("doc.json")))
+		}
+		r.Route("/api", func(r chi.Router) {
+			r.Get("/status", routerCheck)
+			for i := range routers {
+				r.Mount(routers[i].Pattern, routers[i].Handler)
 			}
-			r.Route("/api", func(r chi.Router) {
-				r.Get("/status", routerCheck)
-				for i := range routers {
-					r.Mount(routers[i].Pattern, routers[i].Handler)
-				}
-
-			})
+		})
 
-			if err := s.runHTTPPublic(r); err != nil {
+		s.publicHTTP = s.newHTTPPublic(r)
+		go func() {
+			if err := s.runHTTPPublic(); err != nil {
 				logger.Error(context.Background(), err.Error())
 				s.publicCloser.CloseAll()
 			}
-		}(routers)
+		}()
 		s.publicCloser.Add(s.closeHTTPPublic)
 	}
 
+	if s.cfg.GrpcPort != nil {
+		s.publicGRPC = s.newGRPCPublic(services)
+		go func() {
+			if err := s.runGRPCPublic(); err != nil {
+				logger.Error(context.Background(), err.Error())
+				s.publicCloser.CloseAll()
+			}
+		}()
+		s.publicCloser.Add(s.closeGRPCPublic)
+	}
+
 	s.publicCloser.Wait()
 	closer.CloseAll()
 	closer.Wait()
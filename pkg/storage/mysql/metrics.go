@@ -0,0 +1,131 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"net"
+	"strings"
+	"sync"
+	"time"
+
+	mysqlDriver "github.com/go-sql-driver/mysql"
+	"github.com/jmoiron/sqlx"
+	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+)
+
+var (
+	registerOnce sync.Once
+
+	queryDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: "db",
+		Subsystem: "mysql",
+		Name:      "query_duration_seconds",
+		Help:      "Duration of MySQL queries.",
+		Buckets:   []float64{.001, .0025, .005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5},
+	}, []string{"database", "node", "operation"})
+
+	queryErrors = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "db",
+		Subsystem: "mysql",
+		Name:      "query_errors_total",
+		Help:      "Number of failed MySQL queries by error class.",
+	}, []string{"database", "node", "operation", "class"})
+)
+
+type metrics struct {
+	database string
+	node     string
+
+	reg   prometheus.Registerer
+	stats prometheus.Collector
+}
+
+// newMetrics registers the pool stats of db. A collector left by an earlier
+// Storage for the same database and node is replaced, so a reconnect exports
+// the stats of the new pool.
+func newMetrics(db *sqlx.DB, database, node string) (*metrics, error) {
+	registerOnce.Do(func() {
+		prometheus.MustRegister(queryDuration, queryErrors)
+	})
+
+	reg := prometheus.WrapRegistererWith(prometheus.Labels{"node": node}, prometheus.DefaultRegisterer)
+	stats := collectors.NewDBStatsCollector(db.DB, database)
+	err := reg.Register(stats)
+
+	var are prometheus.AlreadyRegisteredError
+	if errors.As(err, &are) {
+		reg.Unregister(are.ExistingCollector)
+		err = reg.Register(stats)
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "register db stats collector")
+	}
+
+	return &metrics{database: database, node: node, reg: reg, stats: stats}, nil
+}
+
+// close stops exporting the pool stats.
+func (m *metrics) close() {
+	if m == nil {
+		return
+	}
+	m.reg.Unregister(m.stats)
+}
+
+func (m *metrics) write(_ context.Context, started time.Time, query string, err error) {
+	if m == nil {
+		return
+	}
+
+	op := operation(query)
+	queryDuration.WithLabelValues(m.database, m.node, op).Observe(time.Since(started).Seconds())
+	// An empty result is an answer, not a failed query.
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		queryErrors.WithLabelValues(m.database, m.node, op, errorClass(err)).Inc()
+	}
+}
+
+func operation(query string) string {
+	query = strings.TrimLeft(query, " \t\r\n(")
+	if i := strings.IndexAny(query, " \t\r\n("); i > 0 {
+		query = query[:i]
+	}
+	if query == "" {
+		return "unknown"
+	}
+	return strings.ToLower(query)
+}
+
+func errorClass(err error) string {
+	var myErr *mysqlDriver.MySQLError
+	var netErr net.Error
+
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		return "no_rows"
+	case errors.Is(err, context.DeadlineExceeded):
+		return "timeout"
+	case errors.Is(err, context.Canceled):
+		return "canceled"
+	case errors.Is(err, driver.ErrBadConn), errors.Is(err, mysqlDriver.ErrInvalidConn), errors.As(err, &netErr):
+		return "connection"
+	case errors.As(err, &myErr):
+		switch myErr.Number {
+		case 1062:
+			return "duplicate"
+		case 1205:
+			return "lock_timeout"
+		case 1213:
+			return "deadlock"
+		case 1040, 1203:
+			return "too_many_connections"
+		default:
+			return "mysql"
+		}
+	default:
+		return "other"
+	}
+}
@@ -0,0 +1,79 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	mysqlDriver "github.com/go-sql-driver/mysql"
+	"github.com/jmoiron/sqlx"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestMetricsSkipNoRows(t *testing.T) {
+	m := &metrics{database: "metrics_test", node: "master"}
+	const query = "SELECT 1"
+
+	m.write(context.Background(), time.Now(), query, sql.ErrNoRows)
+	if n := testutil.ToFloat64(queryErrors.WithLabelValues("metrics_test", "master", "select", "no_rows")); n != 0 {
+		t.Fatalf("no rows counted as %v errors", n)
+	}
+
+	m.write(context.Background(), time.Now(), query, &mysqlDriver.MySQLError{Number: 1062})
+	if n := testutil.ToFloat64(queryErrors.WithLabelValues("metrics_test", "master", "select", "duplicate")); n != 1 {
+		t.Fatalf("duplicate counted as %v errors, want 1", n)
+	}
+}
+
+func maxOpenConns(t *testing.T, database string) (float64, bool) {
+	t.Helper()
+	mfs, err := prometheus.DefaultGatherer.Gather()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, mf := range mfs {
+		if mf.GetName() != "go_sql_max_open_connections" {
+			continue
+		}
+		for _, m := range mf.GetMetric() {
+			for _, l := range m.GetLabel() {
+				if l.GetName() == "db_name" && l.GetValue() == database {
+					return m.GetGauge().GetValue(), true
+				}
+			}
+		}
+	}
+	return 0, false
+}
+
+func TestMetricsReplaceStatsOfPreviousPool(t *testing.T) {
+	const database = "metrics_reconnect_test"
+	open := func(maxOpen int) *sqlx.DB {
+		db, _, err := sqlmock.New()
+		if err != nil {
+			t.Fatal(err)
+		}
+		t.Cleanup(func() { _ = db.Close() })
+		db.SetMaxOpenConns(maxOpen)
+		return sqlx.NewDb(db, "mysql")
+	}
+
+	if _, err := newMetrics(open(3), database, "master"); err != nil {
+		t.Fatal(err)
+	}
+	m, err := newMetrics(open(7), database, "master")
+	if err != nil {
+		t.Fatalf("second registration: %v", err)
+	}
+	if n, ok := maxOpenConns(t, database); !ok || n != 7 {
+		t.Fatalf("max open connections = %v (exported %v), want the new pool's 7", n, ok)
+	}
+
+	m.close()
+	if _, ok := maxOpenConns(t, database); ok {
+		t.Fatal("pool stats still exported after close")
+	}
+}
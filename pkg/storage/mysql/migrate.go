@@ -0,0 +1,345 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"io/fs"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	mysqlDriver "github.com/go-sql-driver/mysql"
+	"github.com/pkg/errors"
+)
+
+const (
+	// MySQL error numbers of a missing table and a missing column.
+	errNoSuchTable  = 1146
+	errNoSuchColumn = 1054
+
+	migrationsTable = "schema_migrations"
+	migrationsLock  = "schema_migrations"
+
+	// migrationsLockTimeout is how long a replica waits for another one
+	// that is applying migrations right now, in seconds.
+	migrationsLockTimeout = 300
+)
+
+var migrationFileRe = regexp.MustCompile(`^(\d+)_(.+)\.(up|down)\.sql$`)
+
+type Migration struct {
+	Version uint64
+	Name    string
+	Up      string
+	Down    string
+}
+
+type MigrationStatus struct {
+	Version   uint64
+	Name      string
+	Applied   bool
+	AppliedAt *time.Time
+	// Dirty is set for a migration that failed partway, see Migrator.
+	Dirty bool
+}
+
+// Migrator applies versioned migrations read from an fs.FS. Files are named
+// <version>_<name>.up.sql and <version>_<name>.down.sql, e.g. 0001_create_user.up.sql.
+//
+// A migration runs in a transaction, but MySQL commits DDL statements
+// implicitly, so a failing multi-statement migration can leave the schema half
+// changed. Its schema_migrations row is then kept with dirty set, and Up and
+// Down refuse to run until the schema is repaired by hand and the row is
+// deleted (to run the migration again) or its dirty flag cleared.
+type Migrator struct {
+	db         *sql.DB
+	migrations []Migration
+}
+
+func NewMigrator(cfg NodeConfig, fsys fs.FS) (*Migrator, error) {
+	if fsys == nil {
+		return nil, errors.New("migrations FS is nil")
+	}
+
+	migrations, err := readMigrations(fsys)
+	if err != nil {
+		return nil, err
+	}
+
+	c := driverConfig(cfg)
+	c.MultiStatements = true
+	db, err := openDB(c)
+	if err != nil {
+		return nil, errors.Wrap(err, "open migrations connection")
+	}
+
+	return &Migrator{db: db.DB, migrations: migrations}, nil
+}
+
+func (m *Migrator) Close() error {
+	return m.db.Close()
+}
+
+// Up applies all pending migrations in version order.
+func (m *Migrator) Up(ctx context.Context) error {
+	return m.withLock(ctx, func(conn *sql.Conn) error {
+		applied, err := appliedVersions(ctx, conn)
+		if err != nil {
+			return err
+		}
+		if err := checkDirty(applied); err != nil {
+			return err
+		}
+
+		for i := range m.migrations {
+			mg := m.migrations[i]
+			if _, ok := applied[mg.Version]; ok {
+				continue
+			}
+			_, err := conn.ExecContext(ctx, `INSERT INTO `+migrationsTable+` (version, name, dirty) VALUES (?, ?, TRUE)`, mg.Version, mg.Name)
+			if err != nil {
+				return errors.Wrapf(err, "migration %d_%s up: mark dirty", mg.Version, mg.Name)
+			}
+			if err := apply(ctx, conn, mg.Up, `UPDATE `+migrationsTable+` SET dirty = FALSE WHERE version = ?`, mg.Version); err != nil {
+				return errors.Wrapf(err, "migration %d_%s up", mg.Version, mg.Name)
+			}
+		}
+		return nil
+	})
+}
+
+// Down rolls back the last steps applied migrations.
+func (m *Migrator) Down(ctx context.Context, steps int) error {
+	if steps <= 0 {
+		return errors.New("steps must be positive")
+	}
+
+	return m.withLock(ctx, func(conn *sql.Conn) error {
+		applied, err := appliedVersions(ctx, conn)
+		if err != nil {
+			return err
+		}
+		if err := checkDirty(applied); err != nil {
+			return err
+		}
+
+		for i := len(m.migrations) - 1; i >= 0 && steps > 0; i-- {
+			mg := m.migrations[i]
+			if _, ok := applied[mg.Version]; !ok {
+				continue
+			}
+			if mg.Down == "" {
+				return errors.Errorf("migration %d_%s has no down file", mg.Version, mg.Name)
+			}
+			_, err := conn.ExecContext(ctx, `UPDATE `+migrationsTable+` SET dirty = TRUE WHERE version = ?`, mg.Version)
+			if err != nil {
+				return errors.Wrapf(err, "migration %d_%s down: mark dirty", mg.Version, mg.Name)
+			}
+			if err := apply(ctx, conn, mg.Down, `DELETE FROM `+migrationsTable+` WHERE version = ?`, mg.Version); err != nil {
+				return errors.Wrapf(err, "migration %d_%s down", mg.Version, mg.Name)
+			}
+			steps--
+		}
+		return nil
+	})
+}
+
+// Status lists the migrations and whether they are applied. It only reads,
+// so it doesn't wait for the lock held by a running Up or Down.
+func (m *Migrator) Status(ctx context.Context) ([]MigrationStatus, error) {
+	conn, err := m.db.Conn(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "migrations connection")
+	}
+	defer conn.Close()
+
+	applied, err := appliedVersions(ctx, conn)
+	if isMySQLError(err, errNoSuchTable) {
+		applied, err = nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]MigrationStatus, 0, len(m.migrations))
+	for i := range m.migrations {
+		st := MigrationStatus{Version: m.migrations[i].Version, Name: m.migrations[i].Name}
+		if a, ok := applied[m.migrations[i].Version]; ok {
+			st.Applied = true
+			st.AppliedAt = &a.at
+			st.Dirty = a.dirty
+		}
+		res = append(res, st)
+	}
+	return res, nil
+}
+
+// withLock runs f on a single connection holding a MySQL advisory lock, so
+// replicas starting at the same time apply migrations one after another.
+func (m *Migrator) withLock(ctx context.Context, f func(conn *sql.Conn) error) error {
+	conn, err := m.db.Conn(ctx)
+	if err != nil {
+		return errors.Wrap(err, "migrations connection")
+	}
+	defer conn.Close()
+
+	var locked sql.NullInt64
+	err = conn.QueryRowContext(ctx, `SELECT GET_LOCK(?, ?)`, migrationsLock, migrationsLockTimeout).Scan(&locked)
+	if err != nil {
+		return errors.Wrap(err, "acquire migrations lock")
+	}
+	if !locked.Valid || locked.Int64 != 1 {
+		return errors.New("acquire migrations lock: timed out")
+	}
+	defer conn.ExecContext(context.Background(), `SELECT RELEASE_LOCK(?)`, migrationsLock)
+
+	if err := createMigrationsTable(ctx, conn); err != nil {
+		return err
+	}
+
+	return f(conn)
+}
+
+func createMigrationsTable(ctx context.Context, conn *sql.Conn) error {
+	_, err := conn.ExecContext(ctx, `CREATE TABLE IF NOT EXISTS `+migrationsTable+` (
+		version BIGINT UNSIGNED NOT NULL PRIMARY KEY,
+		name VARCHAR(255) NOT NULL,
+		applied_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+		dirty BOOLEAN NOT NULL DEFAULT FALSE
+	)`)
+	if err != nil {
+		return errors.Wrap(err, "create migrations table")
+	}
+
+	// Tables created before the dirty flag lack the column.
+	var n int
+	err = conn.QueryRowContext(ctx, `SELECT COUNT(*) FROM information_schema.COLUMNS
+		WHERE TABLE_SCHEMA = DATABASE() AND TABLE_NAME = ? AND COLUMN_NAME = 'dirty'`, migrationsTable).Scan(&n)
+	if err != nil {
+		return errors.Wrap(err, "inspect migrations table")
+	}
+	if n == 0 {
+		_, err = conn.ExecContext(ctx, `ALTER TABLE `+migrationsTable+` ADD COLUMN dirty BOOLEAN NOT NULL DEFAULT FALSE`)
+		if err != nil {
+			return errors.Wrap(err, "add dirty column to migrations table")
+		}
+	}
+	return nil
+}
+
+func apply(ctx context.Context, conn *sql.Conn, script, record string, args ...interface{}) error {
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	if strings.TrimSpace(script) != "" {
+		if _, err = tx.ExecContext(ctx, script); err != nil {
+			_ = tx.Rollback()
+			return err
+		}
+	}
+	if _, err = tx.ExecContext(ctx, record, args...); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+
+	return tx.Commit()
+}
+
+type appliedMigration struct {
+	name  string
+	at    time.Time
+	dirty bool
+}
+
+func appliedVersions(ctx context.Context, conn *sql.Conn) (map[uint64]appliedMigration, error) {
+	rows, err := conn.QueryContext(ctx, `SELECT version, name, applied_at, dirty FROM `+migrationsTable)
+	if isMySQLError(err, errNoSuchColumn) {
+		// Status reads tables that Up has not given a dirty column yet.
+		rows, err = conn.QueryContext(ctx, `SELECT version, name, applied_at, FALSE FROM `+migrationsTable)
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "select applied migrations")
+	}
+	defer rows.Close()
+
+	applied := make(map[uint64]appliedMigration)
+	for rows.Next() {
+		var version uint64
+		var a appliedMigration
+		if err := rows.Scan(&version, &a.name, &a.at, &a.dirty); err != nil {
+			return nil, errors.Wrap(err, "scan applied migration")
+		}
+		applied[version] = a
+	}
+	return applied, rows.Err()
+}
+
+func isMySQLError(err error, number uint16) bool {
+	var myErr *mysqlDriver.MySQLError
+	return errors.As(err, &myErr) && myErr.Number == number
+}
+
+// checkDirty fails if a migration failed partway before.
+func checkDirty(applied map[uint64]appliedMigration) error {
+	for version, a := range applied {
+		if a.dirty {
+			return errors.Errorf("migration %d_%s is dirty: it failed partway, repair the schema, "+
+				"then delete its %s row to run it again or clear its dirty flag", version, a.name, migrationsTable)
+		}
+	}
+	return nil
+}
+
+func readMigrations(fsys fs.FS) ([]Migration, error) {
+	entries, err := fs.ReadDir(fsys, ".")
+	if err != nil {
+		return nil, errors.Wrap(err, "read migrations dir")
+	}
+
+	byVersion := make(map[uint64]*Migration)
+	for _, e := range entries {
+		if e.IsDir() {
+			continue
+		}
+		match := migrationFileRe.FindStringSubmatch(e.Name())
+		if match == nil {
+			continue
+		}
+
+		version, err := strconv.ParseUint(match[1], 10, 64)
+		if err != nil {
+			return nil, errors.Wrapf(err, "migration %s: version", e.Name())
+		}
+		body, err := fs.ReadFile(fsys, e.Name())
+		if err != nil {
+			return nil, errors.Wrapf(err, "migration %s", e.Name())
+		}
+
+		mg, ok := byVersion[version]
+		if !ok {
+			mg = &Migration{Version: version, Name: match[2]}
+			byVersion[version] = mg
+		}
+		if mg.Name != match[2] {
+			return nil, fmt.Errorf("migration %d: conflicting names %q and %q", version, mg.Name, match[2])
+		}
+		if match[3] == "up" {
+			mg.Up = string(body)
+		} else {
+			mg.Down = string(body)
+		}
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, mg := range byVersion {
+		migrations = append(migrations, *mg)
+	}
+	sort.Slice(migrations, func(i, j int) bool { return migrations[i].Version < migrations[j].Version })
+
+	return migrations, nil
+}
@@ -0,0 +1,175 @@
+package mysql
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	mysqlDriver "github.com/go-sql-driver/mysql"
+)
+
+func TestReadMigrations(t *testing.T) {
+	fsys := fstest.MapFS{
+		"0010_add_index.up.sql":     {Data: []byte("CREATE INDEX i ON user (email);")},
+		"0002_create_user.up.sql":   {Data: []byte("CREATE TABLE user (id INT);")},
+		"0002_create_user.down.sql": {Data: []byte("DROP TABLE user;")},
+		"0001_init.up.sql":          {Data: []byte("")},
+		"README.md":                 {Data: []byte("not a migration")},
+		"0003_bad.sql":              {Data: []byte("ignored, no direction")},
+		"sub/0004_nested.up.sql":    {Data: []byte("ignored, not in the root")},
+	}
+
+	got, err := readMigrations(fsys)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []Migration{
+		{Version: 1, Name: "init"},
+		{Version: 2, Name: "create_user", Up: "CREATE TABLE user (id INT);", Down: "DROP TABLE user;"},
+		{Version: 10, Name: "add_index", Up: "CREATE INDEX i ON user (email);"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("readMigrations =\n%+v\nwant\n%+v", got, want)
+	}
+}
+
+func TestReadMigrationsConflictingNames(t *testing.T) {
+	fsys := fstest.MapFS{
+		"0001_create_user.up.sql":    {Data: []byte("CREATE TABLE user (id INT);")},
+		"0001_create_users.down.sql": {Data: []byte("DROP TABLE user;")},
+	}
+	if _, err := readMigrations(fsys); err == nil || !strings.Contains(err.Error(), "conflicting names") {
+		t.Fatalf("readMigrations error = %v, want conflicting names", err)
+	}
+}
+
+func newTestMigrator(t *testing.T, migrations ...Migration) (*Migrator, sqlmock.Sqlmock) {
+	t.Helper()
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherRegexp))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = db.Close() })
+	return &Migrator{db: db, migrations: migrations}, mock
+}
+
+func expectLock(mock sqlmock.Sqlmock) {
+	mock.ExpectQuery(`SELECT GET_LOCK`).WillReturnRows(sqlmock.NewRows([]string{"locked"}).AddRow(1))
+	mock.ExpectExec(`CREATE TABLE IF NOT EXISTS schema_migrations`).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery(`information_schema.COLUMNS`).WillReturnRows(sqlmock.NewRows([]string{"n"}).AddRow(1))
+}
+
+func appliedRows() *sqlmock.Rows {
+	return sqlmock.NewRows([]string{"version", "name", "applied_at", "dirty"})
+}
+
+func TestUpLeavesFailedMigrationDirty(t *testing.T) {
+	m, mock := newTestMigrator(t,
+		Migration{Version: 1, Name: "init", Up: "CREATE TABLE a (id INT)"},
+		Migration{Version: 2, Name: "second", Up: "CREATE TABLE b (id INT); CREATE TABLE c (id INT)"},
+	)
+
+	expectLock(mock)
+	mock.ExpectQuery(`SELECT version, name, applied_at, dirty FROM schema_migrations`).
+		WillReturnRows(appliedRows().AddRow(1, "init", time.Now(), false))
+	mock.ExpectExec(`INSERT INTO schema_migrations \(version, name, dirty\) VALUES \(\?, \?, TRUE\)`).
+		WithArgs(2, "second").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectBegin()
+	mock.ExpectExec(`CREATE TABLE b`).WillReturnError(&mysqlDriver.MySQLError{Number: 1050, Message: "Table 'c' already exists"})
+	mock.ExpectRollback()
+	mock.ExpectExec(`SELECT RELEASE_LOCK`).WillReturnResult(sqlmock.NewResult(0, 0))
+
+	err := m.Up(context.Background())
+	if err == nil || !strings.Contains(err.Error(), "migration 2_second up") {
+		t.Fatalf("Up error = %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestUpClearsDirtyFlag(t *testing.T) {
+	m, mock := newTestMigrator(t, Migration{Version: 1, Name: "init", Up: "CREATE TABLE a (id INT)"})
+
+	expectLock(mock)
+	mock.ExpectQuery(`SELECT version, name, applied_at, dirty`).WillReturnRows(appliedRows())
+	mock.ExpectExec(`INSERT INTO schema_migrations`).WithArgs(1, "init").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectBegin()
+	mock.ExpectExec(`CREATE TABLE a`).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(`UPDATE schema_migrations SET dirty = FALSE WHERE version = \?`).WithArgs(1).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+	mock.ExpectExec(`SELECT RELEASE_LOCK`).WillReturnResult(sqlmock.NewResult(0, 0))
+
+	if err := m.Up(context.Background()); err != nil {
+		t.Fatalf("Up: %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestUpRefusesDirtyMigration(t *testing.T) {
+	m, mock := newTestMigrator(t,
+		Migration{Version: 1, Name: "init", Up: "CREATE TABLE a (id INT)"},
+		Migration{Version: 2, Name: "second", Up: "CREATE TABLE b (id INT)"},
+	)
+
+	expectLock(mock)
+	mock.ExpectQuery(`SELECT version, name, applied_at, dirty`).
+		WillReturnRows(appliedRows().AddRow(1, "init", time.Now(), true))
+	mock.ExpectExec(`SELECT RELEASE_LOCK`).WillReturnResult(sqlmock.NewResult(0, 0))
+
+	err := m.Up(context.Background())
+	if err == nil || !strings.Contains(err.Error(), "1_init is dirty") {
+		t.Fatalf("Up error = %v, want dirty", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestStatusTakesNoLock(t *testing.T) {
+	m, mock := newTestMigrator(t,
+		Migration{Version: 1, Name: "init"},
+		Migration{Version: 2, Name: "second"},
+	)
+
+	// Any GET_LOCK query would fail the expectations.
+	at := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	mock.ExpectQuery(`SELECT version, name, applied_at, dirty`).
+		WillReturnRows(appliedRows().AddRow(1, "init", at, true))
+
+	status, err := m.Status(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []MigrationStatus{
+		{Version: 1, Name: "init", Applied: true, AppliedAt: &at, Dirty: true},
+		{Version: 2, Name: "second"},
+	}
+	if !reflect.DeepEqual(status, want) {
+		t.Fatalf("Status = %+v, want %+v", status, want)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestStatusWithoutMigrationsTable(t *testing.T) {
+	m, mock := newTestMigrator(t, Migration{Version: 1, Name: "init"})
+
+	mock.ExpectQuery(`SELECT version`).WillReturnError(&mysqlDriver.MySQLError{Number: errNoSuchTable})
+
+	status, err := m.Status(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(status) != 1 || status[0].Applied {
+		t.Fatalf("Status = %+v", status)
+	}
+}
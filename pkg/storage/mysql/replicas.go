@@ -0,0 +1,158 @@
+package mysql
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"example/pkg/goruntime"
+	"example/pkg/logger"
+)
+
+type Balancer string
+
+const (
+	BalancerRoundRobin Balancer = "round_robin"
+	BalancerLeastConn  Balancer = "least_conn"
+
+	defaultHealthCheckInterval = 5 * time.Second
+)
+
+// cluster routes reads between healthy replicas and falls back to master when
+// none of them is available.
+type cluster struct {
+	master   *Storage
+	replicas []*Storage
+	balancer Balancer
+
+	next atomic.Uint64
+
+	stop chan struct{}
+	wg   sync.WaitGroup
+
+	closeOnce sync.Once
+	closeErr  error
+}
+
+func openReplica(cfg NodeConfig, node string, withMetrics bool) (*Storage, error) {
+	db, err := openDB(driverConfig(cfg))
+	if err != nil {
+		return nil, err
+	}
+	db.DB.SetMaxOpenConns(int(cfg.MaxOpen))
+
+	r := &Storage{db: db, node: node, database: cfg.Database}
+	if withMetrics {
+		r.m, err = newMetrics(db, cfg.Database, node)
+		if err != nil {
+			_ = db.Close()
+			return nil, err
+		}
+	}
+	return r, nil
+}
+
+func (c *cluster) slave() *Storage {
+	n := uint64(len(c.replicas))
+	if n == 0 {
+		return c.master
+	}
+
+	if c.balancer == BalancerLeastConn {
+		var best *Storage
+		bestInUse := 0
+		for _, r := range c.replicas {
+			if !r.healthy.Load() {
+				continue
+			}
+			if inUse := r.db.Stats().InUse; best == nil || inUse < bestInUse {
+				best, bestInUse = r, inUse
+			}
+		}
+		if best != nil {
+			return best
+		}
+		return c.master
+	}
+
+	start := c.next.Add(1)
+	for i := uint64(0); i < n; i++ {
+		if r := c.replicas[(start+i)%n]; r.healthy.Load() {
+			return r
+		}
+	}
+	return c.master
+}
+
+func (c *cluster) watch(interval time.Duration) {
+	if len(c.replicas) == 0 {
+		return
+	}
+	if interval <= 0 {
+		interval = defaultHealthCheckInterval
+	}
+
+	c.checkReplicas(interval)
+
+	c.wg.Add(1)
+	goruntime.Go(context.Background(), "mysql-replicas-healthcheck", func(context.Context) {
+		defer c.wg.Done()
+		t := time.NewTicker(interval)
+		defer t.Stop()
+		for {
+			select {
+			case <-c.stop:
+				return
+			case <-t.C:
+				c.checkReplicas(interval)
+			}
+		}
+	})
+}
+
+func (c *cluster) checkReplicas(timeout time.Duration) {
+	for _, r := range c.replicas {
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		err := r.db.PingContext(ctx)
+		cancel()
+		r.setHealthy(err)
+	}
+}
+
+func (s *Storage) setHealthy(err error) {
+	healthy := err == nil
+	if s.healthy.Swap(healthy) == healthy {
+		return
+	}
+	if healthy {
+		logger.Warnf(context.Background(), "mysql %s: back to healthy", s.node)
+	} else {
+		logger.Errorf(context.Background(), "mysql %s: marked unhealthy, reads fall back: %v", s.node, err)
+	}
+}
+
+// close stops the health checks and closes every node. Later calls return the
+// result of the first one.
+func (c *cluster) close() error {
+	c.closeOnce.Do(func() {
+		close(c.stop)
+		c.wg.Wait()
+
+		var errs []error
+		c.master.m.close()
+		if err := c.master.db.Close(); err != nil {
+			errs = append(errs, fmt.Errorf("db master: %w", err))
+		}
+		for _, r := range c.replicas {
+			r.m.close()
+			if err := r.db.Close(); err != nil {
+				errs = append(errs, fmt.Errorf("db %s: %w", r.node, err))
+			}
+		}
+		c.closeErr = errors.Join(errs...)
+	})
+	return c.closeErr
+}
@@ -8,6 +8,7 @@ import (
 	"github.com/pkg/errors"
 	"golang.org/x/net/context"
 	"io/fs"
+	"sync/atomic"
 	"time"
 )
 
@@ -16,13 +17,25 @@ type NodeConfig struct {
 	Port     string
 	User     string
 	Password string
-	Database string
-	MaxOpen  uint
-	TimeOut  time.Duration
+	// PasswordFunc, if set, replaces Password for every new connection, so a
+	// rotated password is used without a restart.
+	PasswordFunc func() string
+	Database     string
+	MaxOpen      uint
+	TimeOut      time.Duration
 }
 
 type Config struct {
-	DBconfig    NodeConfig
+	DBconfig NodeConfig
+	// Replicas serve reads made through Slave(). Writes, prepared statements
+	// and transactions always go to DBconfig.
+	Replicas            []NodeConfig
+	Balancer            Balancer
+	HealthCheckInterval time.Duration
+	// RouteReads also sends reads of the Storage returned by New to replicas,
+	// giving up read-your-writes for its callers.
+	RouteReads bool
+
 	Metrics     bool
 	Migrations  bool
 	MigrationFS fs.FS
@@ -30,6 +43,11 @@ type Config struct {
 
 var ErrNoRows = sql.ErrNoRows
 
+// IsDuplicate reports whether err is a unique key violation.
+func IsDuplicate(err error) bool {
+	return err != nil && errorClass(err) == "duplicate"
+}
+
 type MySQL interface {
 	GetContext(ctx context.Context, dest interface{}, query string, args ...interface{}) error
 	SelectContext(ctx context.Context, dest interface{}, query string, args ...interface{}) error
@@ -40,51 +58,151 @@ type MySQL interface {
 }
 
 type Storage struct {
-	db *sqlx.DB
+	db       *sqlx.DB
+	m        *metrics
+	node     string
+	database string
+
+	healthy atomic.Bool
+	cluster *cluster
+	// route sends reads to a replica picked by the cluster instead of db.
+	route bool
 }
 
 func New(cfg Config) (*Storage, error) {
-	masterDB, err := sqlx.Connect("mysql", dsn(cfg.DBconfig))
+	if cfg.Migrations {
+		if err := migrateUp(cfg); err != nil {
+			return nil, fmt.Errorf("migrations: %v", err)
+		}
+	}
+
+	masterDB, err := openDB(driverConfig(cfg.DBconfig))
 	if err != nil {
 		return nil, fmt.Errorf("master DB: %v", err)
 	}
+	if err := masterDB.Ping(); err != nil {
+		_ = masterDB.Close()
+		return nil, fmt.Errorf("master DB: %v", err)
+	}
 	masterDB.DB.SetMaxOpenConns(int(cfg.DBconfig.MaxOpen))
 
+	master := &Storage{
+		db:       masterDB,
+		node:     "master",
+		database: cfg.DBconfig.Database,
+	}
+	master.healthy.Store(true)
+
+	if cfg.Metrics {
+		master.m, err = newMetrics(masterDB, cfg.DBconfig.Database, master.node)
+		if err != nil {
+			_ = masterDB.Close()
+			return nil, fmt.Errorf("master DB metrics: %v", err)
+		}
+	}
+
+	c := &cluster{
+		master:   master,
+		balancer: cfg.Balancer,
+		stop:     make(chan struct{}),
+	}
+	master.cluster = c
+
+	for i := range cfg.Replicas {
+		r, err := openReplica(cfg.Replicas[i], fmt.Sprintf("replica-%d", i), cfg.Metrics)
+		if err != nil {
+			_ = c.close()
+			return nil, fmt.Errorf("replica DB %d: %v", i, err)
+		}
+		r.cluster = c
+		c.replicas = append(c.replicas, r)
+	}
+	c.watch(cfg.HealthCheckInterval)
+
 	s := &Storage{
-		db: masterDB,
+		db:       masterDB,
+		m:        master.m,
+		node:     master.node,
+		database: master.database,
+		cluster:  c,
+		route:    cfg.RouteReads,
 	}
 
-	return s, err
+	return s, nil
 }
 
-func (s *Storage) Master() *Storage {
+// NewFromDB wraps an open connection as a Storage without replicas, e.g. one
+// opened with a mocked driver in tests.
+func NewFromDB(db *sqlx.DB) *Storage {
+	s := &Storage{db: db, node: "master"}
+	s.healthy.Store(true)
+	s.cluster = &cluster{master: s, stop: make(chan struct{})}
 	return s
 }
 
+func migrateUp(cfg Config) error {
+	m, err := NewMigrator(cfg.DBconfig, cfg.MigrationFS)
+	if err != nil {
+		return err
+	}
+	defer m.Close()
+
+	return m.Up(context.Background())
+}
+
+// Master returns the storage bound to the master node.
+func (s *Storage) Master() *Storage {
+	return s.cluster.master
+}
+
+// Slave returns the storage bound to a healthy replica, or to master if there
+// are no replicas or none of them is healthy.
 func (s *Storage) Slave() *Storage {
+	return s.cluster.slave()
+}
+
+func (s *Storage) reader() *Storage {
+	if s.route {
+		return s.cluster.slave()
+	}
 	return s
 }
 
+func (s *Storage) write(ctx context.Context, started time.Time, query string, err error) {
+	s.m.write(ctx, started, query, err)
+	traceQuery(ctx, started, s.database, s.node, query, err)
+	if err != nil && s.db != s.cluster.master.db && errorClass(err) == "connection" {
+		s.setHealthy(err)
+	}
+}
+
+// Check pings the database, so Storage can be registered as a healthcheck.Checker.
+func (s *Storage) Check(ctx context.Context) error {
+	return s.db.PingContext(ctx)
+}
+
 func (s *Storage) GetContext(ctx context.Context, dest interface{}, query string, args ...interface{}) error {
-	//started := time.Now()
-	err := s.db.GetContext(ctx, dest, query, args...)
-	//s.m.witre(ctx, started, query, err)
+	r := s.reader()
+	started := time.Now()
+	err := r.db.GetContext(ctx, dest, query, args...)
+	r.write(ctx, started, query, err)
 
 	return err
 }
 
 func (s *Storage) SelectContext(ctx context.Context, dest interface{}, query string, args ...interface{}) error {
-	//started := time.Now()
-	err := s.db.SelectContext(ctx, dest, query, args...)
-	//s.m.write(ctx, started, query, err)
+	r := s.reader()
+	started := time.Now()
+	err := r.db.SelectContext(ctx, dest, query, args...)
+	r.write(ctx, started, query, err)
 
 	return err
 }
 
 func (s *Storage) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
-	//started := time.Now()
+	started := time.Now()
 	res, err := s.db.ExecContext(ctx, query, args...)
-	//s.m.write(ctx, started, query, err)
+	s.write(ctx, started, query, err)
 
 	return res, err
 }
@@ -126,12 +244,15 @@ func (s *Storage) PrepareContext(ctx context.Context, query string) (*Stmt, erro
 	if err != nil {
 		return nil, err
 	}
-	return &Stmt{stmt: stmt}, nil
+	return &Stmt{query: query, stmt: stmt, m: s.m, database: s.database, node: s.node}, nil
 }
 
 type Stmt struct {
-	query string
-	stmt  *sqlx.Stmt
+	query    string
+	stmt     *sqlx.Stmt
+	m        *metrics
+	database string
+	node     string
 }
 
 func (s *Stmt) Close() error {
@@ -139,38 +260,55 @@ func (s *Stmt) Close() error {
 }
 
 func (s *Stmt) GetContext(ctx context.Context, dest interface{}, args ...interface{}) error {
-	//started := time.Now()
+	started := time.Now()
 	err := s.stmt.GetContext(ctx, dest, args...)
-	//s.m.write(ctx, started, s.query, err)
+	s.write(ctx, started, err)
 
 	return err
 }
 
 func (s *Stmt) SelectContext(ctx context.Context, dest interface{}, args ...interface{}) error {
-	//started := time.Now()
+	started := time.Now()
 	err := s.stmt.SelectContext(ctx, dest, args...)
-	//s.m.write(ctx, started, s.query, err)
+	s.write(ctx, started, err)
 
 	return err
 }
 
 func (s *Stmt) ExecContext(ctx context.Context, args ...interface{}) (sql.Result, error) {
-	//started := time.Now()
+	started := time.Now()
 	res, err := s.stmt.ExecContext(ctx, args...)
-	//s.m.write(ctx, started, s.query, err)
+	s.write(ctx, started, err)
 
 	return res, err
 }
 
+func (s *Stmt) write(ctx context.Context, started time.Time, err error) {
+	s.m.write(ctx, started, s.query, err)
+	traceQuery(ctx, started, s.database, s.node, s.query, err)
+}
+
+// SetMaxOpenConns changes the connection pool size of the node at runtime.
+func (s *Storage) SetMaxOpenConns(n uint) {
+	s.db.DB.SetMaxOpenConns(int(n))
+}
+
+// Close closes master and every replica.
 func (s *Storage) Close() error {
-	if err := s.db.Close(); err != nil {
-		return errors.Wrap(err, "db master")
-	}
+	return s.cluster.close()
+}
 
-	return nil
+// openDB opens a pool with a connector rather than a DSN, which would drop
+// the password callback.
+func openDB(c *mysqlDriver.Config) (*sqlx.DB, error) {
+	conn, err := mysqlDriver.NewConnector(c)
+	if err != nil {
+		return nil, err
+	}
+	return sqlx.NewDb(sql.OpenDB(conn), "mysql"), nil
 }
 
-func dsn(cfg NodeConfig) string {
+func driverConfig(cfg NodeConfig) *mysqlDriver.Config {
 	c := mysqlDriver.NewConfig()
 	c.User = cfg.User
 	c.Passwd = cfg.Password
@@ -179,6 +317,16 @@ func dsn(cfg NodeConfig) string {
 	c.DBName = cfg.Database
 	c.ParseTime = true
 	c.Timeout = cfg.TimeOut
+	// Report matched rather than changed rows so an UPDATE that writes the
+	// current values is not mistaken for a missing row.
+	c.ClientFoundRows = true
+	if cfg.PasswordFunc != nil {
+		password := cfg.PasswordFunc
+		_ = c.Apply(mysqlDriver.BeforeConnect(func(_ context.Context, c *mysqlDriver.Config) error {
+			c.Passwd = password()
+			return nil
+		}))
+	}
 
-	return c.FormatDSN()
+	return c
 }
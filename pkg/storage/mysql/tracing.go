@@ -0,0 +1,37 @@
+package mysql
+
+import (
+	"errors"
+	"time"
+
+	"example/pkg/tracing"
+
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+	"golang.org/x/net/context"
+)
+
+// traceQuery records a finished query as a child span of the span in ctx.
+// ErrNoRows is not an error of the span.
+func traceQuery(ctx context.Context, started time.Time, database, node, query string, err error) {
+	if !trace.SpanFromContext(ctx).SpanContext().IsValid() {
+		return
+	}
+
+	op := operation(query)
+	_, span := tracing.Start(ctx, "mysql "+op,
+		trace.WithTimestamp(started),
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			semconv.DBSystemMySQL,
+			semconv.DBNamespace(database),
+			semconv.DBOperationName(op),
+			semconv.DBQueryText(query),
+			semconv.PeerService(node),
+		),
+	)
+	if errors.Is(err, ErrNoRows) {
+		err = nil
+	}
+	tracing.End(span, err)
+}
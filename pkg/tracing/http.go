@@ -0,0 +1,67 @@
+package tracing
+
+import (
+	"context"
+	"net/http"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// ExtractHTTP returns ctx with the remote span context of the W3C headers of r.
+func ExtractHTTP(ctx context.Context, r *http.Request) context.Context {
+	return otel.GetTextMapPropagator().Extract(ctx, propagation.HeaderCarrier(r.Header))
+}
+
+// InjectHTTP adds the W3C headers of the span in ctx to h.
+func InjectHTTP(ctx context.Context, h http.Header) {
+	otel.GetTextMapPropagator().Inject(ctx, propagation.HeaderCarrier(h))
+}
+
+// SetHTTPStatus records the response status on span, 5xx is an error.
+func SetHTTPStatus(span trace.Span, status int) {
+	span.SetAttributes(semconv.HTTPResponseStatusCode(status))
+	if status >= http.StatusInternalServerError {
+		span.SetStatus(codes.Error, http.StatusText(status))
+	}
+}
+
+// Transport starts a client span for every request and propagates it to the
+// server in the W3C headers.
+type Transport struct {
+	Base http.RoundTripper
+}
+
+// NewTransport wraps base, http.DefaultTransport if nil.
+func NewTransport(base http.RoundTripper) *Transport {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return &Transport{Base: base}
+}
+
+func (t *Transport) RoundTrip(r *http.Request) (*http.Response, error) {
+	ctx, span := Start(r.Context(), "HTTP "+r.Method,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			semconv.HTTPRequestMethodKey.String(r.Method),
+			semconv.URLFull(r.URL.Redacted()),
+			semconv.ServerAddress(r.URL.Hostname()),
+		),
+	)
+
+	r = r.Clone(ctx)
+	InjectHTTP(ctx, r.Header)
+
+	resp, err := t.Base.RoundTrip(r)
+	if err != nil {
+		End(span, err)
+		return nil, err
+	}
+	SetHTTPStatus(span, resp.StatusCode)
+	span.End()
+	return resp, nil
+}
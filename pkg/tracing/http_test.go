@@ -0,0 +1,53 @@
+package tracing
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestTransportPropagatesSpan(t *testing.T) {
+	rec := recordSpans(t)
+
+	var remote trace.SpanContext
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		remote = trace.SpanContextFromContext(ExtractHTTP(r.Context(), r))
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	t.Cleanup(srv.Close)
+
+	ctx, parent := Start(context.Background(), "parent")
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, srv.URL, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp, err := (&http.Client{Transport: NewTransport(nil)}).Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	parent.End()
+
+	if req.Header.Get("traceparent") != "" {
+		t.Fatal("the caller's request was modified")
+	}
+
+	spans := rec.Ended()
+	if len(spans) != 2 {
+		t.Fatalf("ended %d spans, want the client span and its parent", len(spans))
+	}
+	client := spans[0]
+	if client.SpanKind() != trace.SpanKindClient || client.Parent().SpanID() != parent.SpanContext().SpanID() {
+		t.Fatalf("client span = %s %s, parent %s", client.Name(), client.SpanKind(), client.Parent().SpanID())
+	}
+	if !remote.IsRemote() || remote.TraceID() != parent.SpanContext().TraceID() || remote.SpanID() != client.SpanContext().SpanID() {
+		t.Fatalf("server saw %v, want the client span %v", remote, client.SpanContext())
+	}
+	if client.Status().Code != codes.Error {
+		t.Errorf("client span status = %v, want an error for 502", client.Status())
+	}
+}
@@ -0,0 +1,79 @@
+package tracing
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+// restoreGlobals puts back the provider and propagator a test replaced.
+func restoreGlobals(t *testing.T) {
+	tp, p := otel.GetTracerProvider(), otel.GetTextMapPropagator()
+	t.Cleanup(func() {
+		otel.SetTracerProvider(tp)
+		otel.SetTextMapPropagator(p)
+	})
+}
+
+// recordSpans installs a provider sampling every span into the returned
+// recorder and the W3C propagator.
+func recordSpans(t *testing.T) *tracetest.SpanRecorder {
+	restoreGlobals(t)
+	rec := tracetest.NewSpanRecorder()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(rec)))
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	return rec
+}
+
+func TestInitInvalidExporter(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  Config
+		want string
+	}{
+		{name: "unknown exporter", cfg: Config{Exporter: "jaeger"}, want: `unknown exporter "jaeger"`},
+		{name: "file in a missing directory", cfg: Config{Exporter: ExporterFile, File: filepath.Join(t.TempDir(), "missing", "spans.json")}, want: "create file exporter"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			restoreGlobals(t)
+			shutdown, err := Init(tt.cfg)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Fatalf("Init error = %v, want %q", err, tt.want)
+			}
+			if shutdown != nil {
+				t.Fatal("Init returned a shutdown func with an error")
+			}
+		})
+	}
+}
+
+func TestInitFileExporter(t *testing.T) {
+	restoreGlobals(t)
+	file := filepath.Join(t.TempDir(), "spans.json")
+
+	shutdown, err := Init(Config{ServiceName: "test", Ratio: 1, Exporter: ExporterFile, File: file})
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, span := Start(context.Background(), "exported")
+	span.End()
+	if err := shutdown(); err != nil {
+		t.Fatal(err)
+	}
+
+	b, err := os.ReadFile(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(b), `"Name":"exported"`) {
+		t.Fatalf("span not written to %s: %s", file, b)
+	}
+}